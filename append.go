@@ -0,0 +1,118 @@
+package goget
+
+import (
+	"reflect"
+)
+
+// Append appends a value to the slice of an object by path, and writes the grown slice back into its parent,
+// converting the value to the element type unless option Type. Keys are resolved like [Set]. A nil interface,
+// such as a missing value of map[string]any, becomes []any.
+//
+// The object must be a pointer, map or slice, a slice object itself can only be appended through a pointer.
+func Append(obj any, value any, opt Option, paths ...string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	segs, parseErr := parsePaths(paths)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	grow := func(slice reflect.Value) (reflect.Value, *QueryError) {
+		elem, err := convertValue(reflect.ValueOf(value), slice.Type().Elem(), opt&Type == Type)
+		if err != nil {
+			return slice, err
+		}
+
+		return reflect.Append(slice, elem), nil
+	}
+	if err := updateSlice(reflect.ValueOf(obj), opt, segs, grow); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Insert like [Append], but inserts a value at index of the slice, shifting the following elements.
+// The index is in the range of 0 to the length of the slice, a negative index counts from the end.
+func Insert(obj any, index int, value any, opt Option, paths ...string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	segs, parseErr := parsePaths(paths)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	grow := func(slice reflect.Value) (reflect.Value, *QueryError) {
+		i := index
+		if i < 0 {
+			i += slice.Len()
+		}
+		if i < 0 || i > slice.Len() {
+			return slice, newQueryError(nil, ErrNotFound, "[insert] index %d out of range [0:%d]", index, slice.Len())
+		}
+
+		elem, err := convertValue(reflect.ValueOf(value), slice.Type().Elem(), opt&Type == Type)
+		if err != nil {
+			return slice, err
+		}
+
+		return insertElem(slice, i, elem), nil
+	}
+	if err := updateSlice(reflect.ValueOf(obj), opt, segs, grow); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// insertElem returns a new slice with an element inserted at index, the original backing array is not modified.
+func insertElem(slice reflect.Value, index int, elem reflect.Value) reflect.Value {
+	inserted := reflect.MakeSlice(slice.Type(), 0, slice.Len()+1)
+	inserted = reflect.AppendSlice(inserted, slice.Slice(0, index))
+	inserted = reflect.Append(inserted, elem)
+	return reflect.AppendSlice(inserted, slice.Slice(index, slice.Len()))
+}
+
+// updateSlice replaces the slice of an object by segments with the result of grow.
+// The slice may be in an interface or pointed to by a pointer.
+func updateSlice(root reflect.Value, opt Option, segs []segment, grow func(slice reflect.Value) (reflect.Value, *QueryError)) *QueryError {
+	u := &updater{opt: opt}
+	u.update = func(old reflect.Value) (reflect.Value, *QueryError) {
+		value := old
+		switch {
+		case value.Kind() == reflect.Interface && value.IsNil():
+			value = reflect.ValueOf([]any{})
+		case value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer:
+			if value.IsNil() {
+				return old, newQueryError(nil, ErrNotFound, "[slice] nil %s", value.Type())
+			}
+			value = writable(value.Elem())
+		}
+		if value.Kind() != reflect.Slice {
+			return old, newQueryError(nil, ErrTypeMatch, "[slice] need slice got %s", value.Type())
+		}
+
+		grown, err := grow(writable(value))
+		if err != nil {
+			return old, err
+		}
+
+		if old.Kind() == reflect.Pointer {
+			value.Set(grown)
+			return old, nil
+		}
+		return grown, nil
+	}
+
+	return u.updateRoot(root, segs)
+}
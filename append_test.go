@@ -0,0 +1,86 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	tests := []updateTest{
+		{[]string{"tags"}, "d", None, nil, []string{"a", "b", "c", "d"}, 0},
+		{[]string{"tags"}, 1, None, nil, []string{"a", "b", "c", "1"}, 0},
+		{[]string{"tags"}, 1, Type, nil, nil, ErrTypeMatch},
+		{[]string{"address", "tags"}, "t", None, nil, []string{"t"}, 0},
+		{[]string{"home", "tags"}, "t", None, nil, []string{"t"}, 0},
+		{[]string{"meta", "addr", "tags"}, "y", None, nil, []string{"x", "y"}, 0},
+		{[]string{"meta", "list"}, "s", None, nil, []any{1, 2, 3, "s"}, 0},
+		{[]string{"meta", "new"}, "n", None, nil, []any{"n"}, 0},
+		{[]string{"meta", "count"}, 2, None, nil, nil, ErrTypeMatch},
+		{[]string{"lists", "a"}, "2", None, nil, []int{1, 2}, 0},
+		{[]string{"lists", "b"}, 1, None, nil, []int{1}, 0},
+		{[]string{"ptr"}, 2, None, nil, &[]int{1, 2}, 0},
+		{[]string{"secret"}, 3, None, nil, []int{1, 2, 3}, 0},
+		{[]string{"secret"}, 3, Safe, nil, nil, ErrNotFound},
+		{[]string{"scores"}, 1, None, nil, nil, ErrTypeMatch},
+		{[]string{"meta", "x", "list"}, 1, None, nil, nil, ErrNotFound},
+		{[]string{"..", "tags"}, "t", None, nil, nil, ErrUnsupported},
+	}
+
+	testUpdates(t, tests, func(p *testPerson, tt updateTest) error {
+		return Append(p, tt.value, tt.opt, tt.paths...)
+	})
+
+	assert := assert.New(t)
+
+	// Slice objects are appended through a pointer
+	s := []int{1}
+	assert.Error(Append(s, 2, None))
+	assert.NoError(Append(&s, 2, None))
+	assert.Equal([]int{1, 2}, s)
+	nested := [][]int{{1}}
+	assert.NoError(Append(nested, 2, None, "0"))
+	assert.Equal([][]int{{1, 2}}, nested)
+}
+
+func TestInsert(t *testing.T) {
+	tests := []struct {
+		index int
+		value any
+		want  []string
+		err   bool
+	}{
+		{0, "x", []string{"x", "a", "b", "c"}, false},
+		{1, "x", []string{"a", "x", "b", "c"}, false},
+		{3, "x", []string{"a", "b", "c", "x"}, false},
+		{-1, "x", []string{"a", "b", "x", "c"}, false},
+		{-3, "x", []string{"x", "a", "b", "c"}, false},
+		{4, "x", nil, true},
+		{-4, "x", nil, true},
+		{0, 1, []string{"1", "a", "b", "c"}, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		tags := []string{"a", "b", "c"}
+		obj := map[string]any{"meta": map[string]any{"tags": tags}}
+		err := Insert(obj, tt.index, tt.value, None, "meta,tags")
+		if tt.err {
+			assert.Errorf(err, "index: %d", tt.index)
+			continue
+		}
+
+		if !assert.NoErrorf(err, "index: %d", tt.index) {
+			continue
+		}
+		assert.Equalf(tt.want, Any(obj, "meta,tags"), "index: %d", tt.index)
+
+		// The original backing array is not modified
+		assert.Equalf([]string{"a", "b", "c"}, tags, "index: %d", tt.index)
+	}
+
+	s := []int{1, 3}
+	assert.NoError(Insert(&s, 1, 2, None))
+	assert.Equal([]int{1, 2, 3}, s)
+	assert.Error(Insert(&s, 0, "x", None))
+	assert.Error(Insert(&s, 0, 1, None, "0"))
+}
@@ -71,3 +71,30 @@ func BenchmarkSliceNative(b *testing.B) {
 		_ = benchPerson.tags[3].(Address).street
 	}
 }
+
+func BenchmarkMapCompiled(b *testing.B) {
+	p := gg.MustCompile("address,city")
+	for i := 0; i < b.N; i++ {
+		p.Str(benchPersonMap, gg.N)
+	}
+}
+
+func BenchmarkStructCompiled(b *testing.B) {
+	p := gg.MustCompile("address,city")
+	for i := 0; i < b.N; i++ {
+		p.Str(benchPerson, gg.N)
+	}
+}
+
+func BenchmarkSliceCompiled(b *testing.B) {
+	p := gg.MustCompile("tags,City=Mesa,street")
+	for i := 0; i < b.N; i++ {
+		p.Str(benchPerson, gg.N)
+	}
+}
+
+func BenchmarkStructField(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gg.StringResult(benchAddr, gg.N, "city")
+	}
+}
@@ -0,0 +1,35 @@
+package goget
+
+import (
+	"reflect"
+)
+
+// Delete removes the element of an object by path: map keys are deleted, slice elements are removed and the
+// shortened slice is written back into its parent, struct fields and array elements are zeroed.
+// The object must be a pointer, map or slice, a slice object can only be shortened through a pointer.
+//
+// Keys are resolved like [Set], a wildcard deletes every child and a filter deletes the first matched element, or every
+// one if specified by the filter. A missing element is an error.
+func Delete(obj any, opt Option, paths ...string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	segs, parseErr := parsePaths(paths)
+	if parseErr != nil {
+		return parseErr
+	}
+	if len(segs) == 0 {
+		return newQueryError(nil, ErrNotFound, "[delete] empty path")
+	}
+
+	u := &updater{opt: opt, remove: true}
+	if err := u.updateRoot(reflect.ValueOf(obj), segs); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	rome := testAddress{City: "Rome", Street: "Main St"}
+	oslo := testAddress{City: "Oslo", Street: "Oak St"}
+	pine := testAddress{City: "Rome", Street: "Pine St"}
+
+	tests := []updateTest{
+		{[]string{"name"}, nil, None, nil, "", 0},
+		{[]string{"home"}, nil, None, nil, (*testAddress)(nil), 0},
+		{[]string{"home", "city"}, nil, None, nil, "", 0},
+		{[]string{"friends", "0"}, nil, None, []string{"friends"}, []testAddress{oslo, pine}, 0},
+		{[]string{"friends", "-1"}, nil, None, []string{"friends"}, []testAddress{rome, oslo}, 0},
+		{[]string{"friends", "city=Rome"}, nil, None, []string{"friends"}, []testAddress{oslo, pine}, 0},
+		{[]string{"friends", "?city=Rome"}, nil, None, []string{"friends"}, []testAddress{oslo}, 0},
+		{[]string{"friends", "*"}, nil, None, []string{"friends", "len()"}, 0, 0},
+		{[]string{"friends", "city=Paris"}, nil, None, nil, nil, ErrNotFound},
+		{[]string{"friends", "3"}, nil, None, nil, nil, ErrNotFound},
+		{[]string{"friends", "1", "city"}, nil, None, nil, "", 0},
+		{[]string{"scores", "1"}, nil, None, []string{"scores"}, [3]int{1, 0, 3}, 0},
+		{[]string{"meta", "token"}, nil, None, []string{"meta", "len()"}, 5, 0},
+		{[]string{"meta", "token"}, nil, Case, nil, nil, ErrNotFound},
+		{[]string{"meta", "missing"}, nil, None, nil, nil, ErrNotFound},
+		{[]string{"meta", "inner", "x"}, nil, None, []string{"meta", "inner"}, map[string]any{"y": 2}, 0},
+		{[]string{"meta", "inner", "*"}, nil, None, []string{"meta", "inner"}, map[string]any{}, 0},
+		{[]string{"meta", "list", "1"}, nil, None, []string{"meta", "list"}, []any{1, 3}, 0},
+		{[]string{"meta", "list", "?>1"}, nil, None, []string{"meta", "list"}, []any{1}, 0},
+		{[]string{"contacts", "work", "street"}, nil, None, []string{"contacts", "work"}, testAddress{City: "Kyiv"}, 0},
+		{[]string{"secret", "0"}, nil, None, []string{"secret"}, []int{2}, 0},
+		{[]string{"secret", "0"}, nil, Safe, nil, nil, ErrNotFound},
+		{[]string{"friends", "0:2"}, nil, None, []string{"friends"}, []testAddress{pine}, 0},
+		{[]string{"meta", "list", "::2"}, nil, None, []string{"meta", "list"}, []any{2}, 0},
+		{[]string{"..", "city"}, nil, None, nil, nil, ErrUnsupported},
+		{[]string{"friends", "keys()"}, nil, None, nil, nil, ErrUnsupported},
+	}
+
+	testUpdates(t, tests, func(p *testPerson, tt updateTest) error {
+		friends := p.Friends
+		err := Delete(p, tt.opt, tt.paths...)
+
+		// The original slice is not modified
+		assert.Equalf(t, "Main St", friends[0].Street, "paths: %v", tt.paths)
+		return err
+	})
+
+	assert := assert.New(t)
+
+	// Maps are updated in place, slice objects are shortened through a pointer
+	m := map[string]any{"a": 1, "b": 2}
+	assert.NoError(Delete(m, None, "a"))
+	assert.Equal(map[string]any{"b": 2}, m)
+	s := []int{1, 2, 3}
+	assert.Error(Delete(s, None, "0"))
+	assert.Equal([]int{1, 2, 3}, s)
+	assert.NoError(Delete(&s, None, "0"))
+	assert.Equal([]int{2, 3}, s)
+
+	assert.Error(Delete(m, None))
+	assert.Error(Delete(testAddress{}, None, "city"))
+	assert.Error(Delete(m, None, "x,y"))
+}
@@ -0,0 +1,215 @@
+package goget
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dot like [Any], but queries by a path in dot and bracket notation, such as `address.city`, `meta["e,f"]` or `tags[0]`.
+func Dot(obj any, expr string) any {
+	v, _ := DotResult(obj, None, expr)
+	return v
+}
+
+// DotResult like [AnyResult], but queries by a path in dot and bracket notation, such as `address.city`,
+// `meta["e,f"]` or `tags[0]`.
+//
+// Names after dots are keys as is, "*" is a wildcard and digits are a slice index, ".." searches all descendants.
+// Quoted keys in brackets are taken literally and never reinterpreted. Other keys in brackets are in the same
+// format as a key of paths, such as an index, first, last, range or filter.
+func DotResult(obj any, opt Option, expr string) (_ any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	segs, parseErr := parseDot(expr)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return resultToAny[any](querySegments(obj, opt, defaultTagNames, segs), opt&Type == Type)
+}
+
+// CompileDot parses a path in dot and bracket notation into a [Path] and reports syntax errors, including keys in
+// brackets which are not valid filters, like [Compile].
+func CompileDot(expr string) (*Path, error) {
+	segs, err := parseDot(expr)
+	if err != nil {
+		return nil, err
+	}
+	if err := filterError(segs); err != nil {
+		return nil, err
+	}
+
+	return &Path{segs: segs, tagNames: defaultTagNames}, nil
+}
+
+// MustCompileDot like [CompileDot], but panics on error.
+func MustCompileDot(expr string) *Path {
+	p, err := CompileDot(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// dotParser parses paths in dot and bracket notation:
+//
+//	path    = [ name | bracket ] *( "." name | ".." ( name | bracket ) | bracket )
+//	bracket = "[" ( quoted | key ) "]"
+type dotParser struct {
+	scanner
+}
+
+// parseDot parses a path in dot and bracket notation to segments.
+func parseDot(expr string) ([]segment, *QueryError) {
+	p := &dotParser{scanner{notation: "path", s: expr}}
+
+	segs := make([]segment, 0)
+	for p.pos < len(p.s) {
+		switch {
+		case strings.HasPrefix(p.s[p.pos:], ".."):
+			p.pos += 2
+			segs = append(segs, segment{kind: descentSegment, key: ".."})
+			if p.pos < len(p.s) && p.s[p.pos] == '[' {
+				continue
+			}
+			seg, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			segs = append(segs, seg)
+
+		case p.s[p.pos] == '.' && p.pos > 0:
+			p.pos++
+			seg, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			segs = append(segs, seg)
+
+		case p.s[p.pos] == '[':
+			seg, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segs = append(segs, seg)
+
+		case p.pos == 0:
+			seg, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			segs = append(segs, seg)
+
+		default:
+			return nil, p.errorf("expect . or [")
+		}
+	}
+
+	return segs, nil
+}
+
+// parseName parses a name until the next dot or bracket.
+func (p *dotParser) parseName() (segment, *QueryError) {
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != '.' && p.s[p.pos] != '[' {
+		p.pos++
+	}
+
+	name := p.s[start:p.pos]
+	switch {
+	case name == "":
+		return segment{}, p.errorf("expect name")
+	case name == "*":
+		return segment{kind: wildcardSegment, key: name}, nil
+	case isPointerIndex(name):
+		index, err := strconv.Atoi(name)
+		if err != nil {
+			return segment{}, p.errorf("invalid index %s", name)
+		}
+		return segment{key: name, index: index, isIndex: true}, nil
+	}
+
+	return segment{key: name}, nil
+}
+
+// parseBracket parses a quoted key or a key in brackets.
+func (p *dotParser) parseBracket() (segment, *QueryError) {
+	p.pos++ // [
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.s) {
+		return segment{}, p.errorf("unterminated brackets")
+	}
+
+	// Quoted key
+	if quote := p.s[p.pos]; quote == '"' || quote == '\'' {
+		raw, err := p.scanQuoted()
+		if err != nil {
+			return segment{}, err
+		}
+		key, unquoteErr := unquote(raw)
+		if unquoteErr != nil {
+			return segment{}, p.errorf("invalid string %s", raw)
+		}
+
+		for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+			p.pos++
+		}
+		if p.pos >= len(p.s) || p.s[p.pos] != ']' {
+			return segment{}, p.errorf("expect ]")
+		}
+		p.pos++
+
+		return segment{key: key}, nil
+	}
+
+	// Key until the matching bracket, quoted strings in filters may contain brackets
+	start, depth := p.pos, 0
+	for ; p.pos < len(p.s); p.pos++ {
+		switch p.s[p.pos] {
+		case '"', '\'':
+			if _, err := p.scanQuoted(); err != nil {
+				return segment{}, err
+			}
+			p.pos--
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				key := strings.TrimSpace(p.s[start:p.pos])
+				p.pos++
+				if key == "" {
+					return segment{}, p.errorf("empty key")
+				}
+				return parseKey(key)
+			}
+			depth--
+		}
+	}
+
+	return segment{}, p.errorf("unterminated brackets")
+}
+
+// scanQuoted scans a quoted string with backslash escapes, returns the raw string including quotes.
+func (p *dotParser) scanQuoted() (string, *QueryError) {
+	quote, start := p.s[p.pos], p.pos
+	for p.pos++; p.pos < len(p.s) && p.s[p.pos] != quote; p.pos++ {
+		if p.s[p.pos] == '\\' {
+			p.pos++
+		}
+	}
+	if p.pos >= len(p.s) {
+		p.pos = start
+		return "", p.errorf("unterminated string")
+	}
+	p.pos++
+
+	return p.s[start:p.pos], nil
+}
@@ -0,0 +1,140 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseDot(t *testing.T) {
+	tests := []struct {
+		expr  string
+		kinds []segmentKind
+		keys  []string
+		err   bool
+	}{
+		{"", []segmentKind{}, []string{}, false},
+		{"address.city", []segmentKind{keySegment, keySegment}, []string{"address", "city"}, false},
+		{`meta["e,f"]`, []segmentKind{keySegment, keySegment}, []string{"meta", "e,f"}, false},
+		{`meta['a\'b'][ "x]" ]`, []segmentKind{keySegment, keySegment, keySegment}, []string{"meta", "a'b", "x]"}, false},
+		{`meta[" first "]["="]`, []segmentKind{keySegment, keySegment, keySegment}, []string{"meta", " first ", "="}, false},
+		{"tags[0][-1][first]", []segmentKind{keySegment, keySegment, keySegment, keySegment}, []string{"tags", "0", "-1", "first"}, false},
+		{"tags.0.first", []segmentKind{keySegment, keySegment, keySegment}, []string{"tags", "0", "first"}, false},
+		{"[0].a", []segmentKind{keySegment, keySegment}, []string{"0", "a"}, false},
+		{"tags[*].*", []segmentKind{keySegment, wildcardSegment, wildcardSegment}, []string{"tags", "*", "*"}, false},
+		{"tags[1:3]", []segmentKind{keySegment, rangeSegment}, []string{"tags", "1:3"}, false},
+		{`tags[City="a]"]`, []segmentKind{keySegment, filterSegment}, []string{"tags", `City="a]"`}, false},
+		{"tags[?address.city=Mesa].name", []segmentKind{keySegment, filterSegment, keySegment}, []string{"tags", "?address.city=Mesa", "name"}, false},
+		{"..city", []segmentKind{descentSegment, keySegment}, []string{"..", "city"}, false},
+		{"a..[0]", []segmentKind{keySegment, descentSegment, keySegment}, []string{"a", "..", "0"}, false},
+		{".a", nil, nil, true},
+		{"a.", nil, nil, true},
+		{"a...b", nil, nil, true},
+		{"a[0]b", nil, nil, true},
+		{"a[", nil, nil, true},
+		{"a[]", nil, nil, true},
+		{`a["b"`, nil, nil, true},
+		{`a["b]`, nil, nil, true},
+		{`a["b"c]`, nil, nil, true},
+		{`a["\x"]`, nil, nil, true},
+		{"a[1:2:0]", []segmentKind{keySegment, keySegment}, []string{"a", "1:2:0"}, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		segs, err := parseDot(tt.expr)
+		if tt.err {
+			if assert.NotNilf(err, "expr: %s", tt.expr) {
+				assert.Equalf(ErrSyntax, err.Code, "expr: %s", tt.expr)
+			}
+			continue
+		}
+
+		if !assert.Nilf(err, "expr: %s", tt.expr) {
+			continue
+		}
+		kinds := make([]segmentKind, len(segs))
+		for i, seg := range segs {
+			kinds[i] = seg.kind
+		}
+		assert.Equalf(tt.kinds, kinds, "expr: %s", tt.expr)
+		assert.Equalf(tt.keys, segmentsToKeys(segs), "expr: %s", tt.expr)
+	}
+}
+
+func TestDot(t *testing.T) {
+	type address struct {
+		City   string
+		street string
+	}
+
+	obj := map[string]any{
+		"address": &address{"Mesa", "123 Main St"},
+		"tags":    []any{"a", "b", "c"},
+		"meta": map[string]any{
+			"e,f":   1,
+			" x ":   2,
+			"first": 3,
+			"a=b":   4,
+			"k.v":   5,
+		},
+	}
+
+	tests := []struct {
+		expr string
+		opt  Option
+		want any
+		err  bool
+	}{
+		{"", None, obj, false},
+		{"address.city", None, "Mesa", false},
+		{"address.city", Case, nil, true},
+		{"address.street", Safe, nil, true},
+		{`meta["e,f"]`, None, 1, false},
+		{`meta[" x "]`, None, 2, false},
+		{`meta['first']`, None, 3, false},
+		{`meta["a=b"]`, None, 4, false},
+		{`meta["k.v"]`, None, 5, false},
+		{"tags[0]", None, "a", false},
+		{"tags.1", None, "b", false},
+		{"tags[last]", None, "c", false},
+		{"tags[-1]", None, "c", false},
+		{`tags["0"]`, None, nil, true},
+		{`tags["last"]`, None, nil, true},
+		{"tags[1:]", None, []any{"b", "c"}, false},
+		{"tags[=b]", None, "b", false},
+		{"..city", None, "Mesa", false},
+		{"tags[9]", None, nil, true},
+		{"tags[", None, nil, true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := DotResult(obj, tt.opt, tt.expr)
+		if tt.err {
+			assert.Errorf(err, "expr: %s", tt.expr)
+			continue
+		}
+
+		if !assert.NoErrorf(err, "expr: %s", tt.expr) {
+			continue
+		}
+		assert.Equalf(tt.want, got, "expr: %s", tt.expr)
+	}
+
+	assert.Equal("123 Main St", Dot(obj, "address.street"))
+	assert.Nil(Dot(obj, "address.zip"))
+
+	p := MustCompileDot(`meta["e,f"]`)
+	i, err := p.Int(obj, None)
+	assert.NoError(err)
+	assert.Equal(1, i)
+
+	_, err = CompileDot("a[")
+	assert.Error(err)
+	_, err = CompileDot("tags[a=~*]")
+	var queryErr *QueryError
+	if assert.ErrorAs(err, &queryErr) {
+		assert.Equal(ErrSyntax, queryErr.Code)
+	}
+	assert.Panics(func() { MustCompileDot("a.") })
+}
@@ -120,3 +120,77 @@ func Example() {
 	// map[City:Mesa Country:Malawi] <nil>
 	// map[] QueryError[2]: cannot convert result {Malawi Mesa 123 Main St <nil>} to map[int]interface {}
 }
+
+func ExampleCompile() {
+	addr := Address{
+		Country: "Malawi",
+		City:    "Mesa",
+		street:  "123 Main St",
+	}
+
+	person := &Person{
+		Name:    "Vin Mars",
+		address: &addr,
+		tags:    []any{"tag1", "tag2", addr},
+	}
+
+	// Parse paths once, query many times.
+	street := goget.MustCompile("tags,City=Mesa,street")
+	fmt.Println(street.Str(person, goget.N)) // 123 Main St <nil>
+	fmt.Println(street.Str(addr, goget.N))   // QueryError[1]: [struct] value not found by field tags
+
+	city := goget.MustCompile("address", "city")
+	fmt.Println(city.Any(person, goget.N)) // Mesa <nil>
+	fmt.Println(city.Any(person, goget.C)) // <nil> QueryError[1]: [struct] value not found by field city
+
+	// Output:
+	// 123 Main St <nil>
+	//  QueryError[1]: [struct] value not found by field tags
+	// Mesa <nil>
+	// <nil> QueryError[1]: [struct] value not found by field city
+}
+
+func ExampleAllResult() {
+	person := &Person{
+		Name: "Vin Mars",
+		tags: []any{
+			Address{Country: "Malawi", City: "Mesa"},
+			Address{Country: "Peru", City: "Lima"},
+		},
+	}
+
+	// Wildcard "*" fans out over every map value, struct field and slice element.
+	fmt.Println(goget.All[string](person, "tags,*,City")) // [Mesa Lima]
+
+	matches, err := goget.AllResult[string](person, goget.N, "tags,*,City")
+	for _, m := range matches {
+		fmt.Println(m.Keys, m.Value)
+	}
+	fmt.Println(err)
+
+	// Output:
+	// [Mesa Lima]
+	// [tags 0 City] Mesa
+	// [tags 1 City] Lima
+	// <nil>
+}
+
+func ExampleJSONPath() {
+	person := &Person{
+		Name: "Vin Mars",
+		tags: []any{
+			Address{Country: "Malawi", City: "Mesa"},
+			Address{Country: "Peru", City: "Lima"},
+		},
+	}
+
+	// JSONPath expressions return all result elements.
+	fmt.Println(goget.JSONPath(person, "$.tags[*].City"))                          // [Mesa Lima]
+	fmt.Println(goget.JSONPath(person, "$..[?@.Country == 'Peru'].City"))          // [Lima]
+	fmt.Println(goget.MustCompileJSONPath("$.tags[-1].City").Str(person, goget.N)) // Lima <nil>
+
+	// Output:
+	// [Mesa Lima]
+	// [Lima]
+	// Lima <nil>
+}
@@ -0,0 +1,640 @@
+package goget
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// compareOp is a comparison operator of filter.
+type compareOp uint8
+
+const (
+	opEq       compareOp = iota // =
+	opNe                        // !=
+	opLt                        // <
+	opLe                        // <=
+	opGt                        // >
+	opGe                        // >=
+	opMatch                     // =~ regular expression
+	opPrefix                    // ^= has prefix
+	opSuffix                    // $= has suffix
+	opContains                  // *= contains
+)
+
+// compareTokens are tokens of comparison operators, longer tokens must be matched first.
+var compareTokens = []struct {
+	token string
+	op    compareOp
+}{
+	{"==", opEq},
+	{"!=", opNe},
+	{"<=", opLe},
+	{">=", opGe},
+	{"=~", opMatch},
+	{"^=", opPrefix},
+	{"$=", opSuffix},
+	{"*=", opContains},
+	{"=", opEq},
+	{"<", opLt},
+	{">", opGt},
+}
+
+// timeType is the type of time.Time.
+var timeType = reflect.TypeOf(time.Time{})
+
+// timeLayouts are layouts to parse filter value when the attribute is a time.Time.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// filter is a condition on slice elements, map values or struct fields.
+type filter interface {
+	// match reports whether elem meets the condition.
+	match(elem reflect.Value, w *walker) bool
+}
+
+// literalKind is the kind of a literal value in filter.
+type literalKind uint8
+
+const (
+	bareLiteral   literalKind = iota // unquoted text
+	stringLiteral                    // "text" or 'text'
+	numberLiteral                    // 1, -2.5, 1e3
+	boolLiteral                      // true or false
+	nullLiteral                      // null
+)
+
+// numberRegexp matches number literals.
+var numberRegexp = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][+-]?\d+)?$`)
+
+// comparison is a filter comparing an attribute of elements to value.
+type comparison struct {
+	attr  []segment // nested keys of the attribute, empty means the element itself
+	whole []segment // the dotted attribute as a single key, which is tried first
+	op    compareOp
+	value string
+	kind  literalKind
+	re    *regexp.Regexp // compiled value of opMatch
+	num   bool           // whether a bare value is a number, compared numerically to numeric attributes
+}
+
+// logicalAnd is a filter met when both operands are met: a&&b.
+type logicalAnd struct {
+	left, right filter
+}
+
+// logicalOr is a filter met when either operand is met: a||b.
+type logicalOr struct {
+	left, right filter
+}
+
+// logicalNot is a filter met when operand is not met: !a.
+type logicalNot struct {
+	operand filter
+}
+
+func (f *logicalAnd) match(elem reflect.Value, w *walker) bool {
+	return f.left.match(elem, w) && f.right.match(elem, w)
+}
+
+func (f *logicalOr) match(elem reflect.Value, w *walker) bool {
+	return f.left.match(elem, w) || f.right.match(elem, w)
+}
+
+func (f *logicalNot) match(elem reflect.Value, w *walker) bool {
+	return !f.operand.match(elem, w)
+}
+
+// isFilter reports whether key is a filter.
+func isFilter(key string) bool {
+	return strings.ContainsAny(key, "=<>")
+}
+
+// parseFilter parses a key to filter. The grammar is:
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" or ")" | comparison
+//	comparison = [attr] op value
+func parseFilter(key string) (filter, *QueryError) {
+	p := &filterParser{s: key}
+
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, newQueryError(err, ErrSyntax, "invalid filter: %s", key)
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return nil, newQueryError(nil, ErrSyntax, "invalid filter: unexpected %q at %d: %s", p.s[p.pos], p.pos, key)
+	}
+
+	return f, nil
+}
+
+// filterParser is a recursive descent parser of filter.
+type filterParser struct {
+	s     string
+	pos   int
+	depth int // depth of parentheses
+}
+
+func (p *filterParser) parseOr() (filter, *QueryError) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.skipSpaces(); strings.HasPrefix(p.s[p.pos:], "||"); p.skipSpaces() {
+		p.pos += 2
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalOr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filter, *QueryError) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.skipSpaces(); strings.HasPrefix(p.s[p.pos:], "&&"); p.skipSpaces() {
+		p.pos += 2
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalAnd{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filter, *QueryError) {
+	p.skipSpaces()
+	rest := p.s[p.pos:]
+
+	switch {
+	case strings.HasPrefix(rest, "!") && !strings.HasPrefix(rest, "!="):
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &logicalNot{operand: operand}, nil
+
+	case strings.HasPrefix(rest, "("):
+		p.pos++
+		p.depth++
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if !strings.HasPrefix(p.s[p.pos:], ")") {
+			return nil, newQueryError(nil, ErrSyntax, "missing ) at %d", p.pos)
+		}
+		p.pos++
+		p.depth--
+		return f, nil
+	}
+
+	f, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseComparison parses a comparison in format attr<op>value.
+// A bare value ends before "&&", "||" or the unbalanced ")" of a group, a quoted value ends at the closing quote.
+func (p *filterParser) parseComparison() (*comparison, *QueryError) {
+	start := p.pos
+
+	// Find the operator
+	for ; p.pos < len(p.s); p.pos++ {
+		if p.termEnds(p.pos, 0) {
+			break
+		}
+		// Every operator starts with one of these
+		if strings.IndexByte("=!<>^$*", p.s[p.pos]) < 0 {
+			continue
+		}
+
+		for _, t := range compareTokens {
+			if !strings.HasPrefix(p.s[p.pos:], t.token) {
+				continue
+			}
+
+			f := &comparison{
+				attr: make([]segment, 0),
+				op:   t.op,
+			}
+
+			if attrKey := strings.TrimSpace(p.s[start:p.pos]); attrKey != "" {
+				attr, err := parseAttr(attrKey)
+				if err != nil {
+					return nil, err
+				}
+				f.attr = attr
+				if len(attr) > 1 {
+					f.whole = []segment{{key: attrKey}}
+				}
+			}
+
+			p.pos += len(t.token)
+			if err := p.parseLiteral(f); err != nil {
+				return nil, err
+			}
+
+			if f.op == opMatch {
+				re, err := regexp.Compile(f.value)
+				if err != nil {
+					return nil, newQueryError(err, ErrSyntax, "invalid regexp: %s", f.value)
+				}
+				f.re = re
+			}
+
+			return f, nil
+		}
+	}
+
+	if term := strings.TrimSpace(p.s[start:p.pos]); term != "" {
+		return nil, newQueryError(nil, ErrSyntax, "missing operator: %s", term)
+	}
+	return nil, newQueryError(nil, ErrSyntax, "missing condition at %d", start)
+}
+
+// parseAttr parses the attribute of a comparison to segments.
+// Nested keys are separated by dots, like address.city, and "\." escapes a dot. An existing key of the whole dotted
+// attribute takes precedence when matching.
+func parseAttr(attr string) ([]segment, *QueryError) {
+	if seg, ok := parsePlainKey(attr); ok {
+		return []segment{seg}, nil
+	}
+
+	keys := splitWithEscape(attr, ".", "\\")
+	for i, key := range keys {
+		keys[i] = strings.TrimSpace(key)
+		if keys[i] == "" {
+			return nil, newQueryError(nil, ErrSyntax, "invalid attribute: %s", attr)
+		}
+	}
+
+	segs, err := parseKeys(keys)
+	if err != nil {
+		return nil, newQueryError(err, ErrSyntax, "invalid attribute: %s", attr)
+	}
+	return segs, nil
+}
+
+// parseLiteral parses the value of a comparison: a quoted string, number, true, false, null or bare text.
+func (p *filterParser) parseLiteral(f *comparison) *QueryError {
+	p.skipSpaces()
+
+	// Quoted string
+	if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		quote, start := p.s[p.pos], p.pos
+		for p.pos++; p.pos < len(p.s) && p.s[p.pos] != quote; p.pos++ {
+			if p.s[p.pos] == '\\' {
+				p.pos++
+			}
+		}
+		if p.pos >= len(p.s) {
+			return newQueryError(nil, ErrSyntax, "unterminated string at %d", start)
+		}
+		p.pos++
+
+		value, err := unquote(p.s[start:p.pos])
+		if err != nil {
+			return newQueryError(err, ErrSyntax, "invalid string: %s", p.s[start:p.pos])
+		}
+		f.value, f.kind = value, stringLiteral
+		return nil
+	}
+
+	// Bare value
+	start, balance := p.pos, 0
+	for ; p.pos < len(p.s); p.pos++ {
+		if p.termEnds(p.pos, balance) {
+			break
+		}
+		switch p.s[p.pos] {
+		case '(':
+			balance++
+		case ')':
+			balance--
+		}
+	}
+
+	f.value = strings.TrimSpace(p.s[start:p.pos])
+	switch {
+	case f.value == "true" || f.value == "false":
+		f.kind = boolLiteral
+	case f.value == "null":
+		f.kind = nullLiteral
+	case f.value != "" && strings.ContainsRune("-0123456789", rune(f.value[0])) && numberRegexp.MatchString(f.value):
+		f.kind = numberLiteral
+	default:
+		f.kind, f.num = bareLiteral, isNumberText(f.value)
+	}
+	return nil
+}
+
+// isNumberText reports whether a text is a number by strconv.ParseFloat, which is only tried on texts starting like a
+// number, such as 1, -1, .5, +Inf or NaN, as failures are costly on hot paths.
+func isNumberText(text string) bool {
+	if text == "" || !strings.ContainsRune("+-.0123456789iInN", rune(text[0])) {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(text, 64)
+	return err == nil
+}
+
+// termEnds reports whether a comparison term ends at pos: before "&&", "||" or the unbalanced ")" of a group.
+func (p *filterParser) termEnds(pos int, balance int) bool {
+	rest := p.s[pos:]
+	return strings.HasPrefix(rest, "&&") || strings.HasPrefix(rest, "||") ||
+		(rest[0] == ')' && balance == 0 && p.depth > 0)
+}
+
+// skipSpaces skips spaces.
+func (p *filterParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// parseComparison parses a single term in format attr<op>value to comparison.
+func parseComparison(term string) (*comparison, *QueryError) {
+	p := &filterParser{s: term}
+
+	f, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return nil, newQueryError(nil, ErrSyntax, "unexpected %q at %d: %s", p.s[p.pos], p.pos, term)
+	}
+
+	return f, nil
+}
+
+// unquote interprets a single-quoted or double-quoted string literal with escapes like Go strings.
+func unquote(s string) (string, error) {
+	if s[0] == '\'' {
+		// Convert to double-quoted
+		var b strings.Builder
+		b.WriteByte('"')
+		for i := 1; i < len(s)-1; i++ {
+			switch {
+			case s[i] == '\\' && i+1 < len(s)-1 && s[i+1] == '\'':
+				b.WriteByte('\'')
+				i++
+			case s[i] == '\\' && i+1 < len(s)-1:
+				b.WriteString(s[i : i+2])
+				i++
+			case s[i] == '"':
+				b.WriteString(`\"`)
+			default:
+				b.WriteByte(s[i])
+			}
+		}
+		b.WriteByte('"')
+		s = b.String()
+	}
+
+	return strconv.Unquote(s)
+}
+
+func (f *comparison) match(elem reflect.Value, w *walker) bool {
+	opt := w.opt
+
+	// Query the attribute value corresponding to filter, elements without the attribute never match
+	attrVal, ok := f.attrValue(elem, w)
+	if !ok || !attrVal.IsValid() {
+		return false
+	}
+
+	attrVal, err := toConcreteElem(attrVal, opt&Safe == Safe, 0)
+	if err != nil {
+		return false
+	}
+
+	typeStrict := opt&Type == Type
+
+	switch f.op {
+	case opMatch, opPrefix, opSuffix, opContains:
+		if isNilValue(attrVal) || (typeStrict && attrVal.Kind() != reflect.String) {
+			return false
+		}
+
+		attr := valueToString(attrVal)
+		switch f.op {
+		case opMatch:
+			return f.re.MatchString(attr)
+		case opPrefix:
+			return strings.HasPrefix(attr, f.value)
+		case opSuffix:
+			return strings.HasSuffix(attr, f.value)
+		default:
+			return strings.Contains(attr, f.value)
+		}
+	}
+
+	// Values not comparable are only not equal
+	c, ok := f.compare(attrVal, typeStrict)
+	if !ok {
+		return f.op == opNe
+	}
+
+	switch f.op {
+	case opEq:
+		return c == 0
+	case opNe:
+		return c != 0
+	case opLt:
+		return c < 0
+	case opLe:
+		return c <= 0
+	case opGt:
+		return c > 0
+	case opGe:
+		return c >= 0
+	}
+
+	return false
+}
+
+// attrValue queries the attribute of an element, by the whole dotted attribute as a single key first.
+func (f *comparison) attrValue(elem reflect.Value, w *walker) (reflect.Value, bool) {
+	if f.whole != nil {
+		if attrVal, ok := queryValue(elem, w.opt, w.tagNames, f.whole); ok {
+			return attrVal, true
+		}
+	}
+
+	return queryValue(elem, w.opt, w.tagNames, f.attr)
+}
+
+// compare compares an attribute to the value of comparison, returns -1, 0 or +1, and false ok if not comparable.
+// If typeStrict, the attribute must be of the literal's type, otherwise it is converted on a best-effort basis.
+// A time.Time attribute is compared chronologically to string value in RFC3339, 2006-01-02 15:04:05 or 2006-01-02.
+func (f *comparison) compare(attrVal reflect.Value, typeStrict bool) (int, bool) {
+	if isNilValue(attrVal) || f.kind == nullLiteral {
+		if isNilValue(attrVal) && f.kind == nullLiteral {
+			return 0, true
+		}
+		return 0, false
+	}
+
+	if (f.kind == stringLiteral || f.kind == bareLiteral) && attrVal.Type() == timeType {
+		if t, ok := valueToAny(attrVal).(time.Time); ok {
+			for _, layout := range timeLayouts {
+				if v, err := time.Parse(layout, f.value); err == nil {
+					switch {
+					case t.Before(v):
+						return -1, true
+					case t.After(v):
+						return 1, true
+					default:
+						return 0, true
+					}
+				}
+			}
+		}
+	}
+
+	switch f.kind {
+	case stringLiteral:
+		if typeStrict && attrVal.Kind() != reflect.String {
+			return 0, false
+		}
+		return strings.Compare(valueToString(attrVal), f.value), true
+
+	case numberLiteral:
+		switch attrVal.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			return compareNumbers(valueToString(attrVal), f.value)
+		case reflect.Bool:
+			if typeStrict {
+				return 0, false
+			}
+			return compareNumbers(strconv.Itoa(valueToInt(attrVal)), f.value)
+		case reflect.String:
+			if typeStrict {
+				return 0, false
+			}
+			return compareNumbers(attrVal.String(), f.value)
+		}
+		return 0, false
+
+	case boolLiteral:
+		var attr bool
+		switch attrVal.Kind() {
+		case reflect.Bool:
+			attr = attrVal.Bool()
+		case reflect.String:
+			if typeStrict {
+				return 0, false
+			}
+			b, err := strconv.ParseBool(attrVal.String())
+			if err != nil {
+				return 0, false
+			}
+			attr = b
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			if typeStrict {
+				return 0, false
+			}
+			attr = valueToBool(attrVal)
+		default:
+			return 0, false
+		}
+		switch lit := f.value == "true"; {
+		case attr == lit:
+			return 0, true
+		case lit:
+			return -1, true
+		default:
+			return 1, true
+		}
+	}
+
+	// Bare text: numerically if both are numbers, otherwise by string form
+	attr := valueToString(attrVal)
+	if f.num {
+		if c, ok := compareNumbers(attr, f.value); ok {
+			return c, true
+		}
+	}
+	return strings.Compare(attr, f.value), true
+}
+
+// isNilValue reports whether a concret value is nil.
+func isNilValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Pointer, reflect.Interface, reflect.UnsafePointer:
+		return value.IsNil()
+	}
+	return false
+}
+
+// compareNumbers compares two numbers in string form, returns false ok if either is not a number.
+func compareNumbers(a, b string) (int, bool) {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+
+	// Compare integers exactly
+	if x, err := strconv.ParseInt(a, 10, 64); err == nil {
+		if y, err := strconv.ParseInt(b, 10, 64); err == nil {
+			return compareOrdered(x, y), true
+		}
+	}
+	if x, err := strconv.ParseUint(a, 10, 64); err == nil {
+		if y, err := strconv.ParseUint(b, 10, 64); err == nil {
+			return compareOrdered(x, y), true
+		}
+	}
+
+	x, err := strconv.ParseFloat(a, 64)
+	if err != nil {
+		return 0, false
+	}
+	y, err := strconv.ParseFloat(b, 64)
+	if err != nil {
+		return 0, false
+	}
+	return compareOrdered(x, y), true
+}
+
+// compareOrdered compares two ordered values, returns -1, 0 or +1.
+func compareOrdered[T int64 | uint64 | float64](x, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,625 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseComparison(t *testing.T) {
+	tests := []struct {
+		key   string
+		attr  int
+		op    compareOp
+		value string
+		err   bool
+	}{
+		{"a=b", 1, opEq, "b", false},
+		{"a==b", 1, opEq, "b", false},
+		{"=b", 0, opEq, "b", false},
+		{"a=", 1, opEq, "", false},
+		{"a!=b", 1, opNe, "b", false},
+		{"a<b", 1, opLt, "b", false},
+		{"a<=b", 1, opLe, "b", false},
+		{"a>b", 1, opGt, "b", false},
+		{"a>=b", 1, opGe, "b", false},
+		{"a=~^b.*$", 1, opMatch, "^b.*$", false},
+		{"a^=b", 1, opPrefix, "b", false},
+		{"a$=b", 1, opSuffix, "b", false},
+		{"a*=b", 1, opContains, "b", false},
+		{"$ref=b", 1, opEq, "b", false},
+		{"a=b<c", 1, opEq, "b<c", false},
+		{"a=~(", 0, opMatch, "", true},
+		{"a", 0, opEq, "", true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := parseComparison(tt.key)
+		if tt.err {
+			assert.NotNilf(err, "key: %s", tt.key)
+			continue
+		}
+
+		if !assert.Nilf(err, "key: %s", tt.key) {
+			continue
+		}
+		assert.Equalf(tt.attr, len(got.attr), "key: %s", tt.key)
+		assert.Equalf(tt.op, got.op, "key: %s", tt.key)
+		assert.Equalf(tt.value, got.value, "key: %s", tt.key)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	type item struct {
+		Name  string
+		Price float64
+		Count uint
+		Date  time.Time
+	}
+
+	items := []item{
+		{"apple", 1.5, 10, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"banana", 120, 3, time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{"cherry", 99.9, 25, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	obj := map[string]any{"items": items, "nums": []any{"9", 10, "x"}}
+
+	tests := []struct {
+		paths  string
+		expect []string
+		err    bool
+	}{
+		{"items,name=banana,name", []string{"banana"}, false},
+		{"items,name!=apple,name", []string{"banana"}, false},
+		{"items,price>100,name", []string{"banana"}, false},
+		{"items,price>=99.9,name", []string{"banana"}, false},
+		{"items,price<99.9,name", []string{"apple"}, false},
+		{"items,price<=99.9,name", []string{"apple"}, false},
+		{"items,count>9,name", []string{"apple"}, false},
+		{"items,count<9,name", []string{"banana"}, false},
+		{"items,name>b,name", []string{"banana"}, false},
+		{"items,name=~^c.*y$,name", []string{"cherry"}, false},
+		{"items,name^=ban,name", []string{"banana"}, false},
+		{"items,name$=rry,name", []string{"cherry"}, false},
+		{"items,name*=pl,name", []string{"apple"}, false},
+		{"items,date>2023-03-01,name", []string{"banana"}, false},
+		{"items,date>=2023-06-01T12:00:00Z,name", []string{"banana"}, false},
+		{"items,date<2023-06-01 12:00:00,name", []string{"apple"}, false},
+		{"items,date>2024-01-01,name", nil, true},
+		{"items,price>1000,name", nil, true},
+		{"nums,>9", []string{"10"}, false},
+		{"nums,<10", []string{"9"}, false},
+		{"nums,>w", []string{"x"}, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := AllResult[string](obj, None, tt.paths)
+		if tt.err {
+			assert.Errorf(err, "paths: %s", tt.paths)
+			continue
+		}
+
+		if !assert.NoErrorf(err, "paths: %s", tt.paths) {
+			continue
+		}
+		values := make([]string, len(got))
+		for i, m := range got {
+			values[i] = m.Value
+		}
+		assert.Equalf(tt.expect, values, "paths: %s", tt.paths)
+	}
+}
+
+func TestCompareNumbers(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect int
+		ok     bool
+	}{
+		{"1", "2", -1, true},
+		{"10", "9", 1, true},
+		{"30", "30.0", 0, true},
+		{"-1", "1", -1, true},
+		{"18446744073709551615", "18446744073709551614", 1, true},
+		{"1.5", "1.25", 1, true},
+		{"a", "1", 0, false},
+		{"1", "", 0, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, ok := compareNumbers(tt.a, tt.b)
+		assert.Equalf(tt.ok, ok, "%s vs %s", tt.a, tt.b)
+		assert.Equalf(tt.expect, got, "%s vs %s", tt.a, tt.b)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		key    string
+		expect string
+		err    bool
+	}{
+		{"a=1", "a=1", false},
+		{"a=1&&b=2", "(a=1 && b=2)", false},
+		{"a=1 && b=2 || c=3", "((a=1 && b=2) || c=3)", false},
+		{"a=1||b=2&&c=3", "(a=1 || (b=2 && c=3))", false},
+		{"(a=1||b=2)&&c=3", "((a=1 || b=2) && c=3)", false},
+		{"!a=1", "!a=1", false},
+		{"!(a=1||!b!=2)", "!(a=1 || !b!=2)", false},
+		{"!=1", "!=1", false},
+		{"a=x y", "a=x y", false},
+		{"a=~^(x|y)$", "a=~^(x|y)$", false},
+		{"(a=~^(x|y)$)", "a=~^(x|y)$", false},
+		{"a=f(x)", "a=f(x)", false},
+		{"a=1&&", "", true},
+		{"&&a=1", "", true},
+		{"(a=1", "", true},
+		{"a=1)", "a=1)", false},
+		{"a=1&&b", "", true},
+		{"()", "", true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := parseFilter(tt.key)
+		if tt.err {
+			assert.NotNilf(err, "key: %s", tt.key)
+			continue
+		}
+
+		if !assert.Nilf(err, "key: %s", tt.key) {
+			continue
+		}
+		assert.Equalf(tt.expect, filterToString(got), "key: %s", tt.key)
+	}
+}
+
+// filterToString formats a filter for testing.
+func filterToString(f filter) string {
+	switch f := f.(type) {
+	case *logicalAnd:
+		return "(" + filterToString(f.left) + " && " + filterToString(f.right) + ")"
+	case *logicalOr:
+		return "(" + filterToString(f.left) + " || " + filterToString(f.right) + ")"
+	case *logicalNot:
+		return "!" + filterToString(f.operand)
+	case *comparison:
+		// The shortest token wins
+		op := ""
+		for _, t := range compareTokens {
+			if t.op == f.op {
+				op = t.token
+			}
+		}
+		return strings.Join(segmentsToKeys(f.attr), ".") + op + f.value
+	}
+	return ""
+}
+
+func TestFilterLogic(t *testing.T) {
+	type address struct {
+		Country string
+		City    string
+		Street  string
+	}
+
+	addrs := []address{
+		{"Malawi", "Lilongwe", "1 First St"},
+		{"Peru", "Mesa", "2 Second St"},
+		{"Malawi", "Mesa", "3 Third St"},
+	}
+
+	tests := []struct {
+		paths  string
+		expect string
+		err    bool
+	}{
+		{"Country=Malawi&&City=Mesa,Street", "3 Third St", false},
+		{"Country=Malawi && City=Mesa,Street", "3 Third St", false},
+		{"City=Mesa||City=Lilongwe,Street", "1 First St", false},
+		{"!Country=Malawi,Street", "2 Second St", false},
+		{"Country=Malawi&&!City=Lilongwe,Street", "3 Third St", false},
+		{"(Country=Peru||Country=Malawi)&&City=Mesa&&Street^=3,Street", "3 Third St", false},
+		{"!(Country=Peru||City=Lilongwe),Street", "3 Third St", false},
+		{"Country=Peru&&City=Lilongwe,Street", "", true},
+		{"Country=Peru&&(City=Lilongwe,Street", "", true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := StringResult(addrs, None, tt.paths)
+		if tt.err {
+			assert.Errorf(err, "paths: %s", tt.paths)
+			continue
+		}
+
+		assert.NoErrorf(err, "paths: %s", tt.paths)
+		assert.Equalf(tt.expect, got, "paths: %s", tt.paths)
+	}
+}
+
+func TestFilterEvery(t *testing.T) {
+	type address struct {
+		City   string
+		street string
+	}
+
+	tags := []any{
+		"tag1",
+		address{"Mesa", "1 First St"},
+		address{"Lima", "2 Second St"},
+		map[string]any{"City": "Mesa", "street": "3 Third St"},
+		address{City: "Mesa"},
+	}
+
+	assert := assert.New(t)
+
+	// Plain filter selects the first matched element
+	assert.Equal([]string{"1 First St"}, All[string](tags, "City=Mesa,street"))
+
+	// "?" selects every matched element
+	matches, err := AllResult[string](tags, None, "?City=Mesa,street")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{
+		{[]string{"1", "street"}, "1 First St"},
+		{[]string{"3", "street"}, "3 Third St"},
+		{[]string{"4", "street"}, ""},
+	}, matches)
+
+	assert.Equal([]any{tags[1], tags[3], tags[4]}, All[any](tags, "?City=Mesa"))
+	assert.Equal([]string{"Mesa", "Lima", "Mesa", "Mesa"}, All[string](tags, "?City!=Oslo,City"))
+	assert.Equal([]string{"1 First St", "3 Third St"}, All[string](tags, "?City=Mesa&&street!=,street"))
+
+	// Single result getters return the first one
+	assert.Equal("1 First St", String(tags, "?City=Mesa,street"))
+
+	_, err = AllResult[string](tags, None, "?City=Oslo,street")
+	assert.Error(err)
+	// Unexported fields are skipped when safe
+	matches, err = AllResult[string](tags, Safe, "?City=Mesa,street")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{{[]string{"3", "street"}, "3 Third St"}}, matches)
+}
+
+func TestFilterMap(t *testing.T) {
+	type address struct {
+		Country string
+		City    string
+	}
+
+	addrs := map[string]address{
+		"home":   {"Malawi", "Mesa"},
+		"work":   {"Peru", "Lima"},
+		"office": {"Malawi", "Mesa"},
+	}
+	ids := map[int]address{
+		3: {"Peru", "Lima"},
+		1: {"Malawi", "Mesa"},
+	}
+	literal := map[string]any{
+		"a=b": "literal",
+		"x":   map[string]any{"a": "b"},
+	}
+
+	assert := assert.New(t)
+
+	assert.Equal("Mesa", String(addrs, "Country=Malawi,City"))
+	assert.Equal(addrs["work"], Any(addrs, "City=Lima"))
+
+	// Map values are filtered in the order of sorted keys
+	matches, err := AllResult[string](addrs, None, "?Country=Malawi,City")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{
+		{[]string{"home", "City"}, "Mesa"},
+		{[]string{"office", "City"}, "Mesa"},
+	}, matches)
+
+	matches, err = AllResult[string](ids, None, "?City!=Oslo,Country")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{
+		{[]string{"1", "Country"}, "Malawi"},
+		{[]string{"3", "Country"}, "Peru"},
+	}, matches)
+
+	// Existing string key takes precedence
+	assert.Equal("literal", Any(literal, "a=b"))
+	assert.Equal(map[string]any{"a": "b"}, Any(literal, "?A=b"))
+	assert.Equal(map[string]any{"a": "b"}, Any(literal, "a==b"))
+
+	_, err = AnyResult(addrs, None, "Country=Oslo")
+	assert.Error(err)
+
+	// Existing keys and fields containing operators are looked up as is, a leading backslash escapes other keys
+	operators := map[string]any{"a=b": 1, "x<y": 2, "n>=1": 3, "k=~[": 4, "t": map[string]any{"x<y": 5}}
+	assert.Equal(1, Int(operators, "a=b"))
+	assert.Equal(2, Int(operators, "X<Y"))
+	assert.Equal(3, Int(operators, "n>=1"))
+	assert.Equal(4, Int(operators, `\k=~[`))
+	assert.Equal(5, Int(operators, "t,x<y"))
+
+	// Keys which are not valid filters are literal keys, the syntax error is raised only when applied as a filter
+	invalid := map[string]any{"a=~*": 1, "(x=1": 2, "a=b&&": 3, "l": []any{map[string]any{"a": "b"}}}
+	assert.Equal(1, Int(invalid, "a=~*"))
+	assert.Equal(2, Int(invalid, "(x=1"))
+	assert.Equal(3, Int(invalid, "a=b&&"))
+	for _, paths := range [][]string{{"k=~["}, {"l", "a=~*"}, {"l", "?(x=1"}} {
+		_, err = AnyResult(invalid, None, paths...)
+		var queryErr *QueryError
+		if assert.ErrorAsf(err, &queryErr, "paths: %v", paths) {
+			assert.Equalf(ErrSyntax, queryErr.Code, "paths: %v", paths)
+		}
+	}
+
+	// Compiled paths report the syntax errors up front
+	for _, paths := range [][]string{{"a=~*"}, {"a", "(x=1"}, {"a,b=c&&"}} {
+		_, err = Compile(paths...)
+		var queryErr *QueryError
+		if assert.ErrorAsf(err, &queryErr, "paths: %v", paths) {
+			assert.Equalf(ErrSyntax, queryErr.Code, "paths: %v", paths)
+		}
+	}
+	n, err := MustCompile(`\a=~*`).Int(invalid, None)
+	assert.NoError(err)
+	assert.Equal(1, n)
+
+	type ratio struct {
+		Value int `json:"x<y"`
+		Other int
+	}
+	assert.Equal(6, MayInt(ratio{6, 0}, Tag, "x<y"))
+	assert.Equal(6, MayInt(ratio{6, 0}, None, ">5"))
+}
+
+func TestFilterStruct(t *testing.T) {
+	type address struct {
+		Country string
+		City    string
+	}
+	type contacts struct {
+		Home    address
+		Work    address
+		office  address
+		Hidden  address `json:"-"`
+		Private *address
+		Phone   string
+	}
+
+	c := contacts{
+		Home:    address{"Malawi", "Mesa"},
+		Work:    address{"Peru", "Lima"},
+		office:  address{"Malawi", "Blantyre"},
+		Hidden:  address{"Malawi", "Zomba"},
+		Private: &address{"Malawi", "Dedza"},
+		Phone:   "555",
+	}
+
+	assert := assert.New(t)
+
+	// Struct fields are filtered in their declared order, fields without the attribute never match
+	assert.Equal("Mesa", String(c, "Country=Malawi,City"))
+	matches, err := AllResult[string](c, None, "?Country=Malawi,City")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{
+		{[]string{"Home", "City"}, "Mesa"},
+		{[]string{"office", "City"}, "Blantyre"},
+		{[]string{"Hidden", "City"}, "Zomba"},
+		{[]string{"Private", "City"}, "Dedza"},
+	}, matches)
+	assert.Equal([]string{"555"}, All[string](c, "?=555"))
+
+	// Hidden fields are skipped like by wildcards
+	fields := func(opt Option) []string {
+		matches, err := AllResult[string](c, opt, "?Country=Malawi,City")
+		assert.NoError(err)
+		names := make([]string, len(matches))
+		for i, match := range matches {
+			names[i] = match.Keys[0]
+		}
+		return names
+	}
+	assert.Equal([]string{"Home", "Hidden", "Private"}, fields(Safe))
+	assert.Equal([]string{"Home", "office", "Private"}, fields(Tag))
+
+	_, err = AllResult[string](c, None, "?Country=Oslo,City")
+	assert.Error(err)
+
+	// JSONPath filters select struct fields like object members
+	assert.Equal([]any{"Lima"}, JSONPath(c, "$[?@.Country == 'Peru'].City"))
+
+	// Updates apply to the matched fields
+	assert.NoError(Set(&c, "x", None, "?Country=Malawi", "City"))
+	assert.Equal([]string{"x", "Lima", "x", "x", "x"}, All[string](c, "*,City"))
+}
+
+func TestParseLiteral(t *testing.T) {
+	tests := []struct {
+		key   string
+		kind  literalKind
+		value string
+		err   bool
+	}{
+		{"a=b", bareLiteral, "b", false},
+		{"a= b c ", bareLiteral, "b c", false},
+		{`a="b"`, stringLiteral, "b", false},
+		{`a=" b "`, stringLiteral, " b ", false},
+		{`a='b'`, stringLiteral, "b", false},
+		{`a="x && y || (z"`, stringLiteral, "x && y || (z", false},
+		{`a="say \"hi\""`, stringLiteral, `say "hi"`, false},
+		{`a='it\'s "ok"'`, stringLiteral, `it's "ok"`, false},
+		{`a="中\t"`, stringLiteral, "中\t", false},
+		{`a=""`, stringLiteral, "", false},
+		{"a=30", numberLiteral, "30", false},
+		{"a=-2.5", numberLiteral, "-2.5", false},
+		{"a=1e3", numberLiteral, "1e3", false},
+		{"a=0x10", bareLiteral, "0x10", false},
+		{"a=NaN", bareLiteral, "NaN", false},
+		{"a=true", boolLiteral, "true", false},
+		{"a=false", boolLiteral, "false", false},
+		{"a=True", bareLiteral, "True", false},
+		{"a=null", nullLiteral, "null", false},
+		{`a="b`, bareLiteral, "", true},
+		{`a="b" c`, bareLiteral, "", true},
+		{`a="\q"`, bareLiteral, "", true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := parseComparison(tt.key)
+		if tt.err {
+			assert.NotNilf(err, "key: %s", tt.key)
+			continue
+		}
+
+		if !assert.Nilf(err, "key: %s", tt.key) {
+			continue
+		}
+		assert.Equalf(tt.kind, got.kind, "key: %s", tt.key)
+		assert.Equalf(tt.value, got.value, "key: %s", tt.key)
+	}
+
+	f, err := parseFilter(`a="x)"&&(b='&&'||c=")")`)
+	assert.Nil(err)
+	assert.Equal(`(a=x) && (b=&& || c=)))`, filterToString(f))
+}
+
+func TestFilterLiteral(t *testing.T) {
+	type record struct {
+		Name  string
+		Age   any
+		OK    any
+		Score float64
+		Ptr   *int
+	}
+
+	one := 1
+	records := []record{
+		{"a", 30, true, 1.5, nil},
+		{"b", "30", "true", 30, &one},
+		{"c", 30.0, 1, 0, nil},
+		{"d", nil, false, -1, nil},
+		{"30", "thirty", "yes", 2, nil},
+	}
+
+	tests := []struct {
+		paths  string
+		opt    Option
+		expect []string
+	}{
+		{"?Age=30,Name", None, []string{"a", "b", "c"}},
+		{"?Age=30,Name", Type, []string{"a", "c"}},
+		{`?Age="30",Name`, None, []string{"a", "b", "c"}},
+		{`?Age="30",Name`, Type, []string{"b"}},
+		{"?Age>=30,Name", None, []string{"a", "b", "c"}},
+		{"?Age<100,Name", Type, []string{"a", "c"}},
+		{"?Age!=30,Name", None, []string{"d", "30"}},
+		{"?Age!=30,Name", Type, []string{"b", "d", "30"}},
+		{"?OK=true,Name", None, []string{"a", "b", "c"}},
+		{"?OK=true,Name", Type, []string{"a"}},
+		{"?OK=false,Name", None, []string{"d"}},
+		{"?OK!=true,Name", None, []string{"d", "30"}},
+		{"?OK=1,Name", None, []string{"a", "c"}},
+		{"?Age=null,Name", None, []string{"d"}},
+		{"?Age!=null,Name", None, []string{"a", "b", "c", "30"}},
+		{"?Ptr=null,Name", None, []string{"a", "c", "d", "30"}},
+		{"?Ptr!=null,Name", Type, []string{"b"}},
+		{"?Ptr=1,Name", None, []string{"b"}},
+		{"?Score>1.5,Name", None, []string{"b", "30"}},
+		{"?Score=30,Name", Type, []string{"b"}},
+		{"?Name=30,Name", None, []string{"30"}},
+		{"?Name=30,Name", Type, nil},
+		{`?Name="30",Name`, Type, []string{"30"}},
+		{"?Name>b,Name", None, []string{"c", "d"}},
+		{"?Age^=3,Name", None, []string{"a", "b", "c"}},
+		{"?Age^=3,Name", Type, []string{"b"}},
+		{"?Missing!=1,Name", None, nil},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got := make([]string, 0)
+		matches, err := AllResult[string](records, tt.opt, tt.paths)
+		if tt.expect == nil {
+			assert.Errorf(err, "paths: %s", tt.paths)
+			continue
+		}
+
+		assert.NoErrorf(err, "paths: %s", tt.paths)
+		for _, m := range matches {
+			got = append(got, m.Value)
+		}
+		assert.Equalf(tt.expect, got, "paths: %s", tt.paths)
+	}
+}
+
+func TestParseAttr(t *testing.T) {
+	tests := []struct {
+		attr   string
+		expect []string
+		err    bool
+	}{
+		{"a", []string{"a"}, false},
+		{"a.b", []string{"a", "b"}, false},
+		{" a . b ", []string{"a", "b"}, false},
+		{"a.0.b", []string{"a", "0", "b"}, false},
+		{"a\\.b.c", []string{"a.b", "c"}, false},
+		{"a.*.c", []string{"a", "*", "c"}, false},
+		{"a.", nil, true},
+		{".a", nil, true},
+		{"a..b", nil, true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := parseAttr(tt.attr)
+		if tt.err {
+			assert.NotNilf(err, "attr: %s", tt.attr)
+			continue
+		}
+
+		if assert.Nilf(err, "attr: %s", tt.attr) {
+			assert.Equalf(tt.expect, segmentsToKeys(got), "attr: %s", tt.attr)
+		}
+	}
+}
+
+func TestFilterNested(t *testing.T) {
+	type address struct {
+		City string
+		Geo  map[string]float64
+	}
+	type person struct {
+		Name    string
+		Address *address
+		Tags    []string
+	}
+
+	people := []person{
+		{"a", &address{"Lima", map[string]float64{"lat": -12}}, []string{"x"}},
+		{"b", nil, nil},
+		{"c", &address{"Mesa", map[string]float64{"lat": 33.4}}, []string{"y", "z"}},
+		{"d", &address{"Mesa", map[string]float64{"lat": -1}}, []string{"z"}},
+	}
+	meta := []map[string]any{
+		{"a.b": 1, "a": map[string]any{"b": 2}},
+		{"a": map[string]any{"b": 3}},
+	}
+
+	assert := assert.New(t)
+
+	assert.Equal("c", String(people, "address.city=Mesa,name"))
+	assert.Equal([]string{"c", "d"}, All[string](people, "?address.city=Mesa,name"))
+	assert.Equal([]string{"c"}, All[string](people, "?address.city=Mesa&&address.geo.lat>0,name"))
+	assert.Equal([]string{"a", "d"}, All[string](people, "?address.geo.lat<0,name"))
+	assert.Equal([]string{"c", "d"}, All[string](people, "?tags.last=z,name"))
+	assert.Equal([]string{"c"}, All[string](people, "?tags.0=y||tags.1=y,name"))
+	assert.Equal(1, Int(meta, "a\\.b=1,a.b"))
+
+	// An existing key of the whole dotted attribute takes precedence
+	assert.Equal(1, Int(meta, "a.b=1,a.b"))
+	assert.Equal(nil, Any(meta, "a.b=2,a,b"))
+	assert.Equal(3, Int(meta, "a.b=3,a,b"))
+}
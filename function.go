@@ -0,0 +1,109 @@
+package goget
+
+import (
+	"reflect"
+)
+
+// pathFunction computes a new Value from the node of a path.
+type pathFunction func(value reflect.Value, w *walker) (reflect.Value, *QueryError)
+
+// pathFunctions are the built-in functions by their keys.
+var pathFunctions = map[string]pathFunction{
+	"len()":    lenFunction,
+	"keys()":   keysFunction,
+	"values()": valuesFunction,
+	"type()":   typeFunction,
+}
+
+// walkFunction calls the function of segment on a Value, and search the result by segments.
+func (w *walker) walkFunction(value reflect.Value, seg segment, segs []segment, keys []string) (bool, *QueryError) {
+	result, err := pathFunctions[seg.key](value, w)
+	if err != nil {
+		return false, err
+	}
+
+	stop, err := w.walk(result, segs, w.appendKey(keys, seg.key))
+	if err != nil {
+		return false, newQueryError(err, ErrNotFound, "[function] query keys: %s", segmentsToKeys(segs))
+	}
+	return stop, nil
+}
+
+// lenFunction returns the length of a string, slice, array, map or chan, like the built-in len.
+func lenFunction(value reflect.Value, w *walker) (reflect.Value, *QueryError) {
+	value, err := toConcreteElem(value, w.opt&Safe == Safe, 0)
+	if err != nil {
+		return value, newQueryError(err, ErrNotFound, "[len()] invalid value")
+	}
+
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return reflect.ValueOf(value.Len()), nil
+	}
+	return value, newQueryError(nil, ErrNotFound, "[len()] invalid kind: %s", value.Kind())
+}
+
+// keysFunction returns the sorted keys of a map, the field names of a struct or the indexes of a slice or array.
+// Unexported fields are skipped when safe.
+func keysFunction(value reflect.Value, w *walker) (reflect.Value, *QueryError) {
+	value, err := toConcreteElem(value, w.opt&Safe == Safe, 0)
+	if err != nil {
+		return value, newQueryError(err, ErrNotFound, "[keys()] invalid value")
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		return makeSlice(value.Type().Key(), sortedMapKeys(value)), nil
+	case reflect.Struct:
+		names := make([]string, 0, value.NumField())
+		eachChild(value, w.opt, w.tagNames, func(_ reflect.Value, key string) bool {
+			names = append(names, key)
+			return false
+		})
+		return reflect.ValueOf(names), nil
+	case reflect.Slice, reflect.Array:
+		indexes := make([]int, value.Len())
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return reflect.ValueOf(indexes), nil
+	}
+	return value, newQueryError(nil, ErrNotFound, "[keys()] invalid kind: %s", value.Kind())
+}
+
+// valuesFunction returns the values of a map in the order of sorted keys, the field values of a struct or the
+// elements of a slice or array. Unexported fields are skipped when safe.
+func valuesFunction(value reflect.Value, w *walker) (reflect.Value, *QueryError) {
+	value, err := toConcreteElem(value, w.opt&Safe == Safe, 0)
+	if err != nil {
+		return value, newQueryError(err, ErrNotFound, "[values()] invalid value")
+	}
+
+	elemType := reflect.TypeOf((*any)(nil)).Elem()
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		elemType = value.Type().Elem()
+	case reflect.Struct:
+	default:
+		return value, newQueryError(nil, ErrNotFound, "[values()] invalid kind: %s", value.Kind())
+	}
+
+	elems := make([]reflect.Value, 0)
+	eachChild(value, w.opt, w.tagNames, func(child reflect.Value, _ string) bool {
+		elems = append(elems, child)
+		return false
+	})
+	return makeSlice(elemType, elems), nil
+}
+
+// typeFunction returns the name of the dynamic type of a Value, or "nil".
+func typeFunction(value reflect.Value, _ *walker) (reflect.Value, *QueryError) {
+	for value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() || value.Kind() == reflect.Interface {
+		return reflect.ValueOf("nil"), nil
+	}
+	return reflect.ValueOf(value.Type().String()), nil
+}
@@ -0,0 +1,109 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFunction(t *testing.T) {
+	type address struct {
+		Country string
+		City    string
+		street  string
+	}
+
+	addr := &address{"Malawi", "Mesa", "123 Main St"}
+	obj := map[string]any{
+		"name":    "héllo",
+		"tags":    []string{"a", "b", "c"},
+		"meta":    map[string]int{"b": 2, "a": 1, "c": 3},
+		"ids":     map[int]bool{3: true, 1: false},
+		"address": addr,
+		"none":    nil,
+		"arr":     [2]int{7, 8},
+	}
+
+	tests := []struct {
+		paths []string
+		opt   Option
+		want  any
+		err   bool
+	}{
+		{[]string{"tags", "len()"}, None, 3, false},
+		{[]string{"name", "len()"}, None, 6, false},
+		{[]string{"meta", "len()"}, None, 3, false},
+		{[]string{"arr", "len()"}, None, 2, false},
+		{[]string{"len()"}, None, 7, false},
+		{[]string{"address", "len()"}, None, nil, true},
+		{[]string{"meta", "keys()"}, None, []string{"a", "b", "c"}, false},
+		{[]string{"ids", "keys()"}, None, []int{1, 3}, false},
+		{[]string{"address", "keys()"}, None, []string{"Country", "City", "street"}, false},
+		{[]string{"address", "keys()"}, Safe, []string{"Country", "City"}, false},
+		{[]string{"tags", "keys()"}, None, []int{0, 1, 2}, false},
+		{[]string{"name", "keys()"}, None, nil, true},
+		{[]string{"meta", "values()"}, None, []int{1, 2, 3}, false},
+		{[]string{"address", "values()"}, None, []any{"Malawi", "Mesa", "123 Main St"}, false},
+		{[]string{"address", "values()"}, Safe, []any{"Malawi", "Mesa"}, false},
+		{[]string{"arr", "values()"}, None, []int{7, 8}, false},
+		{[]string{"name", "values()"}, None, nil, true},
+		{[]string{"address", "type()"}, None, "*goget.address", false},
+		{[]string{"tags", "type()"}, None, "[]string", false},
+		{[]string{"none", "type()"}, None, "nil", false},
+		{[]string{"meta", "keys()", "last"}, None, "c", false},
+		{[]string{"meta", "values()", "len()"}, None, 3, false},
+		{[]string{"meta", "keys()", "9"}, None, nil, true},
+		{[]string{"*", "type()"}, None, "*goget.address", false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := AnyResult(obj, tt.opt, tt.paths...)
+		if tt.err {
+			assert.Errorf(err, "paths: %v", tt.paths)
+			continue
+		}
+
+		if !assert.NoErrorf(err, "paths: %v", tt.paths) {
+			continue
+		}
+		assert.Equalf(tt.want, got, "paths: %v", tt.paths)
+	}
+
+	// Results are converted like other values
+	assert.Equal("3", String(obj, "tags,len()"))
+	assert.Equal([]int{1, 2, 3}, Slice[int](obj, "meta,values()"))
+	_, err := StringResult(obj, Type, "tags,len()")
+	assert.Error(err)
+
+	// Existing map keys and struct fields take precedence over functions of the same name
+	type counter struct {
+		Len int `json:"len()"`
+	}
+	colliding := map[string]any{"len()": 1, "keys()": "k", "values()": []int{2}, "type()": "t", "list": []int{1, 2}}
+	assert.Equal(1, Any(colliding, "len()"))
+	assert.Equal("k", Any(colliding, "keys()"))
+	assert.Equal(2, Any(colliding, "values()", "0"))
+	assert.Equal("t", Any(colliding, "type()"))
+	assert.Equal(2, Any(colliding, "list", "len()"))
+	assert.Equal(5, Any(&map[string]int{"len()": 5}, "len()"))
+	assert.Equal(2, Any(map[string]int{"a": 1, "b": 1}, "len()"))
+	field, err := AnyResult(&counter{7}, Tag, "len()")
+	assert.NoError(err)
+	assert.Equal(7, field)
+	_, err = AnyResult(&counter{7}, None, "len()")
+	assert.Error(err)
+	assert.NoError(Set(colliding, 2, None, "len()"))
+	assert.Equal(2, colliding["len()"])
+	c := &counter{}
+	assert.NoError(Set(c, 8, Tag, "len()"))
+	assert.Equal(8, c.Len)
+	assert.Error(Set(map[string]int{"a": 1}, 8, None, "len()"))
+
+	// Quoted keys are never functions
+	assert.Equal(1, Dot(map[string]int{"len()": 1}, `["len()"]`))
+	assert.Equal(3, Dot(obj, "tags[len()]"))
+
+	matches, err := AllResult[int](obj, None, "*,len()")
+	assert.NoError(err)
+	assert.Equal([]string{"arr", "len()"}, matches[0].Keys)
+}
@@ -2,33 +2,45 @@
 package goget
 
 import (
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // QueryError error code
 type ErrCode int
 
 const (
-	ErrNotFound  ErrCode = 1 // Not found by paths
-	ErrTypeMatch ErrCode = 2 // Target type not match
+	ErrNotFound    ErrCode = 1 // Not found by paths
+	ErrTypeMatch   ErrCode = 2 // Target type not match
+	ErrSyntax      ErrCode = 3 // Invalid path syntax
+	ErrAmbiguous   ErrCode = 4 // Key matches more than one element
+	ErrTestFailed  ErrCode = 5 // Test operation of a patch failed
+	ErrUnsupported ErrCode = 6 // Key not supported in updates, such as recursive descent
 )
 
 const (
-	None Option = 0         // Try best
-	Case Option = 1 << iota // Match keys case-sensitive
-	Safe                    // Skip unexported fields of a struct
-	Type                    // Strict match target type
+	None        Option = 0         // Try best
+	Case        Option = 1 << iota // Match keys case-sensitive
+	Safe                           // Skip unexported fields of a struct
+	Type                           // Strict match target type
+	Method                         // Call exported methods without arguments by name
+	Tag                            // Match struct fields by names in json tags, or tags of Path.WithTagNames
+	Unambiguous                    // Error if a key matches more than one map key or struct field case-insensitive
+	Normalize                      // Match keys ignoring case and separators, such as user_name, user-name and userName
 
 	N Option = None
 	C Option = Case
 	S Option = Safe
 	T Option = Type
+	M Option = Method
 
 	CS  = C | S
 	CT  = C | T
@@ -461,8 +473,52 @@ func MustMap[K comparable, E any](obj any, opt Option, paths ...string) map[K]E
 	return r
 }
 
+// Match is a result element and the keys by which it was found.
+type Match[E any] struct {
+	Keys  []string // Concrete keys from the object to the element
+	Value E
+}
+
+// All like [Any], but returns all result elements as E.
+// Paths may fan out, for example by wildcard "*".
+func All[E any](obj any, paths ...string) []E {
+	matches, err := AllResult[E](obj, None, paths...)
+	if err != nil {
+		return nil
+	}
+
+	values := make([]E, len(matches))
+	for i, m := range matches {
+		values[i] = m.Value
+	}
+	return values
+}
+
+// AllResult like [AnyResult], but returns all result elements and the keys by which they were found.
+// Nil elements are returned as zero values of E.
+func AllResult[E any](obj any, opt Option, paths ...string) (_ []Match[E], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	segs, queryErr := parsePaths(paths)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+
+	results, queryErr := queryAll(obj, opt, defaultTagNames, segs)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+
+	return resultsToMatches[E](results, opt&Type == Type)
+}
+
 type QueryError struct {
-	Code   ErrCode // ErrNotFound or ErrTypeMatch
+	Code   ErrCode // ErrNotFound, ErrTypeMatch, ErrSyntax, ErrAmbiguous, ErrTestFailed or ErrUnsupported
 	Detail string
 	cause  error
 }
@@ -523,148 +579,594 @@ func queryResult(obj any, opt Option, paths []string) Result {
 		return Result{val: reflect.ValueOf(obj)}
 	}
 
-	value, err := query(reflect.ValueOf(obj), opt&Case == Case, opt&Safe == Safe, pathsToKeys(paths))
+	// Fast path: follow existing plain keys without parsing paths to segments
+	if value, ok := lookupPaths(reflect.ValueOf(obj), opt, paths); ok {
+		return Result{val: value}
+	}
+
+	segs, err := parsePaths(paths)
 	if err != nil {
 		return Result{err: err}
 	}
 
-	return Result{val: value}
+	return querySegments(obj, opt, defaultTagNames, segs)
 }
 
-// query search a Value by keys and returns the result element.
-func query(value reflect.Value, caseSensitive, safe bool, keys []string) (_ reflect.Value, err *QueryError) {
-	if !value.IsValid() {
-		return value, newQueryError(nil, ErrNotFound, "invalid value")
+// lookupPaths follows the existing keys of paths from a Value, like [lookupKeys]. It returns false ok on any key
+// which is not a plain key or not found, or on escaped commas, all of which are left to parsed segments.
+func lookupPaths(value reflect.Value, opt Option, paths []string) (reflect.Value, bool) {
+	// Check every key before looking up any, so that other paths cost no wasted lookups
+	for _, path := range paths {
+		if strings.Contains(path, `\`) {
+			return value, false
+		}
+		for more := true; more; {
+			var key string
+			key, path, more = strings.Cut(path, ",")
+			if !isPlainKey(key) {
+				return value, false
+			}
+		}
+	}
+
+	for _, path := range paths {
+		for more := true; more; {
+			var key string
+			key, path, more = strings.Cut(path, ",")
+
+			seg, _ := parsePlainKey(strings.TrimSpace(key))
+			var ok bool
+			if value, ok = lookupKey(value, seg, opt, defaultTagNames); !ok {
+				return value, false
+			}
+		}
 	}
 
-	if len(keys) == 0 {
+	return value, true
+}
+
+// query search a Value by segments and returns the first result element.
+func query(value reflect.Value, opt Option, tagNames []string, segs []segment) (_ reflect.Value, err *QueryError) {
+	// Fast path: follow existing keys without walking, the walker takes over on any other segment or missing key
+	root := value
+	value, segs = lookupKeys(value, segs, opt, tagNames)
+	if len(segs) == 0 {
 		return value, nil
 	}
-	currentKey := keys[0]
-	remainKeys := keys[1:]
 
-	// Convert to concret element
+	var result reflect.Value
+	w := &walker{opt: opt, tagNames: tagNames, root: root, visit: func(v reflect.Value, _ []string) bool {
+		result = v
+		return false
+	}}
+
+	if _, err = w.walk(value, segs, nil); err != nil {
+		return value, err
+	}
+
+	return result, nil
+}
+
+// queryValue like [query], but reports only whether an element is found, so missing keys cost no error.
+func queryValue(value reflect.Value, opt Option, tagNames []string, segs []segment) (reflect.Value, bool) {
+	found, remainSegs := lookupKeys(value, segs, opt, tagNames)
+	switch {
+	case len(remainSegs) == 0:
+		return found, true
+	case remainSegs[0].kind == keySegment && opt&Method != Method:
+		// Only a method may be found in place of a missing key
+		return found, false
+	}
+
+	found, err := query(value, opt, tagNames, segs)
+	return found, err == nil
+}
+
+// lookupKeys follows the existing keys of leading key segments from a Value, and returns the last found Value and the
+// remaining segments.
+func lookupKeys(value reflect.Value, segs []segment, opt Option, tagNames []string) (reflect.Value, []segment) {
+	for len(segs) > 0 {
+		child, ok := lookupKey(value, segs[0], opt, tagNames)
+		if !ok {
+			break
+		}
+		value, segs = child, segs[1:]
+	}
+
+	return value, segs
+}
+
+// lookupKey returns the existing map value, struct field or slice element of a Value by a key segment, like
+// [walker.walk] does. It returns false ok for other segments, missing keys and errors, which are left to the walker.
+func lookupKey(value reflect.Value, seg segment, opt Option, tagNames []string) (reflect.Value, bool) {
+	if seg.kind != keySegment {
+		return value, false
+	}
+
+	value, err := toConcreteElem(value, opt&Safe == Safe, 0)
+	if err != nil {
+		return value, false
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		if seg.strict {
+			return value, false
+		}
+		// Fast path: an exact key of map[string]any, the type of decoded JSON objects, needs no reflection
+		if value.Type() == mapStringAnyType && value.CanInterface() {
+			if child, ok := value.Interface().(map[string]any)[seg.key]; ok && child != nil {
+				return reflect.ValueOf(child), true
+			}
+		}
+		keyValue, err := findMapKey(value, seg.key, opt)
+		if err != nil {
+			return value, false
+		}
+		child := value.MapIndex(keyValue)
+		return child, child.IsValid()
+
+	case reflect.Struct:
+		if seg.strict {
+			return value, false
+		}
+		field, ok, err := findStructField(value.Type(), seg.key, opt, tagNames)
+		if err != nil || !ok || (opt&Safe == Safe && !field.IsExported()) {
+			return value, false
+		}
+		child, indexErr := value.FieldByIndexErr(field.Index)
+		return child, indexErr == nil
+
+	case reflect.Slice, reflect.Array:
+		index, ok := sliceIndex(seg, value.Len())
+		if !ok {
+			return value, false
+		}
+		return value.Index(index), true
+	}
+
+	return value, false
+}
+
+// sliceIndex returns the index of a slice or array element by a key segment, negative indexes count from the end.
+// It returns false ok if the key is not an index of the length.
+func sliceIndex(seg segment, length int) (int, bool) {
+	if !seg.isIndex || seg.index >= length || seg.index < -length {
+		return 0, false
+	}
+	if seg.index < 0 {
+		return length + seg.index, true
+	}
+	return seg.index, true
+}
+
+// invalidIndexError returns the error of a key segment which is not an index of a slice or array.
+func invalidIndexError(seg segment) *QueryError {
+	// ConvErr: prevents changing the type of error
+	_, convErr := strconv.Atoi(seg.key)
+	if seg.isIndex {
+		convErr = nil
+	}
+	return newQueryError(convErr, ErrNotFound, "[slice] invalid key: %s", seg.key)
+}
+
+// walker search a Value by segments and visits every result element.
+type walker struct {
+	opt      Option
+	tagNames []string                                      // struct tags to match keys with option Tag
+	root     reflect.Value                                 // the queried object, referenced by filters
+	withKeys bool                                          // record keys of result elements
+	visit    func(value reflect.Value, keys []string) bool // returns false to stop walking
+}
+
+// walk search a Value by segments and visits result elements.
+// It returns whether walking is stopped by visitor, and error if no element found.
+func (w *walker) walk(value reflect.Value, segs []segment, keys []string) (stop bool, err *QueryError) {
+	if !value.IsValid() {
+		return false, newQueryError(nil, ErrNotFound, "invalid value")
+	}
+
+	if len(segs) == 0 {
+		return !w.visit(value, keys), nil
+	}
+	return w.walkSegment(value, segs[0], segs[1:], keys)
+}
+
+// walkSegment like [walker.walk], but search a valid Value by a segment followed by the remaining segments.
+func (w *walker) walkSegment(value reflect.Value, seg segment, remainSegs []segment, keys []string) (stop bool, err *QueryError) {
+	currentKey := seg.key
+
+	safe := w.opt&Safe == Safe
+
+	// A special key of paths yields to an existing map key or struct field of the same key
+	if seg.literal && isLiteralKeyOf(value, currentKey, w.opt, w.tagNames) {
+		seg.kind = keySegment
+	}
+
+	switch seg.kind {
+	case descentSegment:
+		return w.walkDescendants(value, remainSegs, keys, make(map[reference]bool))
+	case unionSegment:
+		return w.walkUnion(value, seg.union, remainSegs, keys)
+	case functionSegment:
+		return w.walkFunction(value, seg, remainSegs, keys)
+	}
+
+	// Convert to concret element, methods may need the original pointer receiver
+	original := value
 	_value, err := toConcreteElem(value, safe, 0)
 	if err != nil {
-		return value, newQueryError(err, ErrNotFound, "invalid key: %s", currentKey)
+		return false, newQueryError(err, ErrNotFound, "invalid key: %s", currentKey)
 	}
 	value = _value
 
+	switch {
+	case seg.kind == wildcardSegment:
+		return w.walkChildren(value, remainSegs, keys)
+	case seg.kind == filterSegment:
+		return w.walkFilter(value, seg, remainSegs, keys)
+	case seg.strict && value.Kind() != reflect.Slice && value.Kind() != reflect.Array:
+		return false, newQueryError(nil, ErrNotFound, "[slice] index %s of %s", currentKey, value.Kind())
+	}
+
 	switch value.Kind() {
 	case reflect.Map:
+		keyValue, findErr := findMapKey(value, currentKey, w.opt)
+		if findErr != nil {
+			return false, findErr
+		}
+
 		// Get key value
-		keyValue := findMapKeyValue(value, currentKey, caseSensitive)
 		fieldValue := value.MapIndex(keyValue)
 		if !fieldValue.IsValid() {
-			return value, newQueryError(nil, ErrNotFound, "[map] value not found by key %s", currentKey)
+			if stop, ok, err := w.walkMethod(original, seg, remainSegs, keys); ok {
+				return stop, err
+			}
+			return false, newQueryError(nil, ErrNotFound, "[map] value not found by key %s", currentKey)
 		}
 
-		value, err = query(fieldValue, caseSensitive, safe, remainKeys)
+		stop, err = w.walk(fieldValue, remainSegs, w.appendKey(keys, mapKeyToString(keyValue)))
 		if err != nil {
-			return value, newQueryError(err, ErrNotFound, "[map] query keys: %s", remainKeys)
+			return false, newQueryError(err, ErrNotFound, "[map] query keys: %s", segmentsToKeys(remainSegs))
 		}
-		return value, nil
+		return stop, nil
 
 	case reflect.Struct:
-		fieldName := findStructFieldName(value, currentKey, caseSensitive)
+		field, ok, findErr := findStructField(value.Type(), currentKey, w.opt, w.tagNames)
+		if findErr != nil {
+			return false, findErr
+		}
+		if !ok || (safe && !field.IsExported()) {
+			if stop, ok, err := w.walkMethod(original, seg, remainSegs, keys); ok {
+				return stop, err
+			}
+		}
 		if safe {
 			// Check unexported field when safe
-			fieldType, ok := value.Type().FieldByName(fieldName)
 			if !ok {
-				return value, newQueryError(nil, ErrNotFound, "[struct] field %s not exists", currentKey)
+				return false, newQueryError(nil, ErrNotFound, "[struct] field %s not exists", currentKey)
 			}
-			if !fieldType.IsExported() {
-				return value, newQueryError(nil, ErrNotFound, "[struct] field %s not exported", currentKey)
+			if !field.IsExported() {
+				return false, newQueryError(nil, ErrNotFound, "[struct] field %s not exported", currentKey)
 			}
 		}
+		if !ok {
+			return false, newQueryError(nil, ErrNotFound, "[struct] value not found by field %s", currentKey)
+		}
 
-		fieldValue := value.FieldByName(fieldName)
-		if !fieldValue.IsValid() {
-			return value, newQueryError(nil, ErrNotFound, "[struct] value not found by field %s", fieldName)
+		// Promoted fields of nil embedded pointers are not found
+		fieldName := field.Name
+		fieldValue, indexErr := value.FieldByIndexErr(field.Index)
+		if indexErr != nil {
+			return false, newQueryError(indexErr, ErrNotFound, "[struct] field %s in nil embedded struct", fieldName)
 		}
 
-		value, err = query(fieldValue, caseSensitive, safe, remainKeys)
+		stop, err = w.walk(fieldValue, remainSegs, w.appendKey(keys, fieldName))
 		if err != nil {
-			return value, newQueryError(err, ErrNotFound, "[struct] error query keys: %s", remainKeys)
+			return false, newQueryError(err, ErrNotFound, "[struct] error query keys: %s", segmentsToKeys(remainSegs))
 		}
-		return value, nil
+		return stop, nil
 
 	case reflect.Slice, reflect.Array:
-		switch {
-		case strings.Contains(currentKey, "="):
-			parts := strings.SplitN(currentKey, "=", 2)
-			if len(parts) != 2 {
-				return value, newQueryError(nil, ErrNotFound, "[slice filter] invalid key format: %s", currentKey)
-			}
-
-			k, queryAttr := parts[0], parts[1]
+		switch seg.kind {
+		case rangeSegment:
+			indices := seg.rng.indices(value.Len())
 
-			for index := 0; index < value.Len(); index++ {
-				indexValue := value.Index(index)
+			// Result is the sub-slice, unless every element is selected
+			if len(remainSegs) == 0 && !seg.every {
+				return !w.visit(subSlice(value, indices), keys), nil
+			}
 
-				// Query the attribute value corresponding to k
-				attrKeys := make([]string, 0)
-				if k != "" {
-					attrKeys = append(attrKeys, k)
+			// Query the remaining paths on each selected element
+			found := false
+			for _, index := range indices {
+				stop, err := w.walk(value.Index(index), remainSegs, w.appendKey(keys, strconv.Itoa(index)))
+				if err == nil {
+					found = true
 				}
-				attrVal, err := query(indexValue, caseSensitive, safe, attrKeys)
-				if err != nil {
-					continue
-				}
-				if !attrVal.IsValid() {
-					continue
-				}
-
-				attrVal, err = toConcreteElem(attrVal, safe, 0)
-				if err != nil {
-					continue
+				if stop {
+					return true, nil
 				}
+			}
 
-				// Determine whether the attribute meets the filter conditions
-				indexAttr := valueToString(attrVal)
-				if indexAttr != queryAttr {
-					continue
-				}
+			if !found {
+				return false, newQueryError(nil, ErrNotFound, "[slice range] no elem by range %s and keys: %s", currentKey, segmentsToKeys(remainSegs))
+			}
+			return false, nil
 
-				// Query the remaining paths
-				_value, err := query(indexValue, caseSensitive, safe, remainKeys)
-				if err != nil {
-					continue
+		default:
+			index, ok := sliceIndex(seg, value.Len())
+			if !ok {
+				if stop, ok, err := w.walkMethod(original, seg, remainSegs, keys); ok {
+					return stop, err
 				}
-				value = _value
+				return false, invalidIndexError(seg)
+			}
 
-				return value, nil
+			stop, err = w.walk(value.Index(index), remainSegs, w.appendKey(keys, strconv.Itoa(index)))
+			if err != nil {
+				return false, newQueryError(err, ErrNotFound, "[slice] query keys: %s", segmentsToKeys(remainSegs))
 			}
+			return stop, nil
+		}
+	default:
+		if stop, ok, err := w.walkMethod(original, seg, remainSegs, keys); ok {
+			return stop, err
+		}
+		return false, newQueryError(nil, ErrNotFound, "invalid kind: %s", value.Kind())
+	}
+}
 
-			return value, newQueryError(nil, ErrNotFound, "[slice filter] no elem by key: %s", currentKey)
+// isLiteralKey reports whether a key is an existing string map key or struct field of a concrete Value, which takes
+// precedence over the wildcard, descent, filter or function of the same key.
+func isLiteralKey(value reflect.Value, key string, opt Option, tagNames []string) bool {
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return false
+		}
+		keyValue, err := findMapKey(value, key, opt)
+		return err == nil && value.MapIndex(keyValue).IsValid()
+	case reflect.Struct:
+		field, ok, err := findStructField(value.Type(), key, opt, tagNames)
+		return err == nil && ok && (opt&Safe != Safe || field.IsExported())
+	}
 
-		default:
-			if strings.ToLower(currentKey) == "first" {
-				currentKey = "0"
-			}
-			if strings.ToLower(currentKey) == "last" {
-				currentKey = strconv.Itoa(value.Len() - 1)
-			}
+	return false
+}
 
-			// ConvErt: prevents changing the type of error
-			index, convErr := strconv.Atoi(currentKey)
-			if convErr != nil || index >= value.Len() || index < -value.Len() {
-				return value, newQueryError(convErr, ErrNotFound, "[slice] invalid key: %s", currentKey)
-			}
+// isLiteralKeyOf like [isLiteralKey], but checks the concrete element of a Value.
+func isLiteralKeyOf(value reflect.Value, key string, opt Option, tagNames []string) bool {
+	value, err := toConcreteElem(value, opt&Safe == Safe, 0)
+	return err == nil && isLiteralKey(value, key, opt, tagNames)
+}
 
-			if index < 0 {
-				index = value.Len() + index
-			}
+// walkChildren search every map value, struct field or slice element of a Value by segments.
+// Map values are walked in the order of sorted keys, so the result is deterministic.
+func (w *walker) walkChildren(value reflect.Value, segs []segment, keys []string) (bool, *QueryError) {
+	found := false
+	next := func(child reflect.Value, key string) bool {
+		stop, err := w.walk(child, segs, w.appendKey(keys, key))
+		if err == nil {
+			found = true
+		}
+		return stop
+	}
 
-			value, err = query(value.Index(index), caseSensitive, safe, remainKeys)
-			if err != nil {
-				return value, newQueryError(err, ErrNotFound, "[slice] query keys: %s", remainKeys)
+	stop, ok := eachChild(value, w.opt, w.tagNames, next)
+	if !ok {
+		return false, newQueryError(nil, ErrNotFound, "[wildcard] invalid kind: %s", value.Kind())
+	}
+	if stop {
+		return true, nil
+	}
+
+	if !found {
+		return false, newQueryError(nil, ErrNotFound, "[wildcard] no elem by keys: %s", segmentsToKeys(segs))
+	}
+	return false, nil
+}
+
+// walkUnion search a Value by each alternative segment followed by segments, results are in the order of alternatives.
+func (w *walker) walkUnion(value reflect.Value, alts []segment, segs []segment, keys []string) (bool, *QueryError) {
+	found := false
+	for _, alt := range alts {
+		stop, err := w.walkSegment(value, alt, segs, keys)
+		if err == nil {
+			found = true
+		}
+		if stop {
+			return true, nil
+		}
+	}
+
+	if !found {
+		return false, newQueryError(nil, ErrNotFound, "[union] no elem by keys: %s", segmentsToKeys(alts))
+	}
+	return false, nil
+}
+
+// walkFilter search map values, struct fields or slice elements which meet the filter by segments.
+// It selects the first matched element, or every matched element if specified by the filter.
+func (w *walker) walkFilter(value reflect.Value, seg segment, segs []segment, keys []string) (bool, *QueryError) {
+	if seg.err != nil {
+		return false, seg.err
+	}
+
+	found, stop := false, false
+	eachChild(value, w.opt, w.tagNames, func(child reflect.Value, key string) bool {
+		if !seg.filter.match(child, w) {
+			return false
+		}
+
+		// Query the remaining paths
+		var err *QueryError
+		stop, err = w.walk(child, segs, w.appendKey(keys, key))
+		if err != nil {
+			return false
+		}
+		found = true
+
+		return stop || !seg.every
+	})
+
+	if stop {
+		return true, nil
+	}
+	if !found {
+		switch value.Kind() {
+		case reflect.Map:
+			return false, newQueryError(nil, ErrNotFound, "[map filter] no elem by key: %s", seg.key)
+		case reflect.Struct:
+			return false, newQueryError(nil, ErrNotFound, "[struct filter] no elem by key: %s", seg.key)
+		}
+		return false, newQueryError(nil, ErrNotFound, "[slice filter] no elem by key: %s", seg.key)
+	}
+	return false, nil
+}
+
+// eachChild calls fn on every map value, struct field or slice element of a Value, until fn returns true.
+// Map values are visited in the order of sorted keys. Hidden fields are skipped, see [isFieldHidden].
+// It returns whether fn stops the iteration, and false ok if the Value is not a container.
+func eachChild(value reflect.Value, opt Option, tagNames []string, fn func(child reflect.Value, key string) bool) (stop bool, ok bool) {
+	switch value.Kind() {
+	case reflect.Map:
+		for _, keyValue := range sortedMapKeys(value) {
+			if fn(value.MapIndex(keyValue), mapKeyToString(keyValue)) {
+				return true, true
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if isFieldHidden(field, opt, tagNames) {
+				continue
+			}
+			if fn(value.Field(i), field.Name) {
+				return true, true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for index := 0; index < value.Len(); index++ {
+			if fn(value.Index(index), strconv.Itoa(index)) {
+				return true, true
 			}
-			return value, nil
 		}
 	default:
-		return value, newQueryError(nil, ErrNotFound, "invalid kind: %s", value.Kind())
+		return false, false
+	}
+
+	return false, true
+}
+
+// isFieldHidden reports whether a struct field is skipped by wildcards, filters and functions: unexported fields with
+// option Safe, and fields ignored by "-" in tags with option Tag.
+func isFieldHidden(field reflect.StructField, opt Option, tagNames []string) bool {
+	return (opt&Safe == Safe && !field.IsExported()) || (opt&Tag == Tag && isTagIgnored(field, tagNames))
+}
+
+// reference is the identity of a pointer, map or slice, used to detect cycles.
+type reference struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// walkDescendants search a Value and all its descendants by segments, in depth-first pre-order.
+// Values referenced by ancestors are skipped, so cyclic objects are walked only once.
+func (w *walker) walkDescendants(value reflect.Value, segs []segment, keys []string, ancestors map[reference]bool) (bool, *QueryError) {
+	// Collect references from the value to its concret element
+	refs := make([]reference, 0, 1)
+	for v := value; v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface; v = v.Elem() {
+		if v.IsNil() {
+			break
+		}
+		if v.Kind() == reflect.Pointer {
+			refs = append(refs, reference{v.Pointer(), v.Type()})
+		}
+	}
+
+	concrete, err := toConcreteElem(value, w.opt&Safe == Safe, 0)
+	if err == nil && (concrete.Kind() == reflect.Map || concrete.Kind() == reflect.Slice) && !concrete.IsNil() {
+		refs = append(refs, reference{concrete.Pointer(), concrete.Type()})
 	}
+
+	// Skip the value referenced by ancestors
+	for _, ref := range refs {
+		if ancestors[ref] {
+			return false, newQueryError(nil, ErrNotFound, "[descent] cyclic keys: %s", keys)
+		}
+	}
+	for _, ref := range refs {
+		ancestors[ref] = true
+	}
+	defer func() {
+		for _, ref := range refs {
+			delete(ancestors, ref)
+		}
+	}()
+
+	found := false
+
+	stop, err := w.walk(value, segs, keys)
+	if err == nil {
+		found = true
+	}
+	if stop {
+		return true, nil
+	}
+
+	next := func(child reflect.Value, key string) bool {
+		stop, err := w.walkDescendants(child, segs, w.appendKey(keys, key), ancestors)
+		if err == nil {
+			found = true
+		}
+		return stop
+	}
+
+	if stop, _ := eachChild(concrete, w.opt, w.tagNames, next); stop {
+		return true, nil
+	}
+
+	if !found {
+		return false, newQueryError(nil, ErrNotFound, "[descent] no elem by keys: %s", segmentsToKeys(segs))
+	}
+	return false, nil
+}
+
+// subSlice returns a new slice of elements of a slice or array by indices.
+func subSlice(value reflect.Value, indices []int) reflect.Value {
+	elems := make([]reflect.Value, len(indices))
+	for i, index := range indices {
+		elems[i] = value.Index(index)
+	}
+
+	return makeSlice(value.Type().Elem(), elems)
+}
+
+// makeSlice returns a new slice of element type with elements.
+func makeSlice(elemType reflect.Type, elems []reflect.Value) reflect.Value {
+	s := reflect.MakeSlice(reflect.SliceOf(elemType), 0, len(elems))
+
+	for _, elem := range elems {
+		// Elements of unexported fields cannot be set directly, copy them via any
+		v := reflect.Zero(elemType)
+		if i := valueToAny(elem); i != nil {
+			v = reflect.ValueOf(i)
+		}
+		s = reflect.Append(s, v)
+	}
+
+	return s
+}
+
+// appendKey returns keys with key appended if keys are recorded.
+func (w *walker) appendKey(keys []string, key string) []string {
+	if !w.withKeys {
+		return nil
+	}
+
+	// Full slice expression: never share the underlying array between branches
+	return append(keys[:len(keys):len(keys)], key)
 }
 
 // resultToAny convert a Result to target type.
@@ -731,6 +1233,24 @@ func resultToAny[E any](result Result, typeStrict bool) (target E, err error) {
 	return target, newQueryError(nil, ErrTypeMatch, "cannot convert result %v to %T", _v, target)
 }
 
+// resultsToMatches convert all result elements to target type.
+func resultsToMatches[E any](results []Match[reflect.Value], typeStrict bool) ([]Match[E], error) {
+	matches := make([]Match[E], 0, len(results))
+	for _, result := range results {
+		var target E
+		if valueToAny(result.Value) != nil {
+			var err error
+			target, err = resultToAny[E](Result{val: result.Value}, typeStrict)
+			if err != nil {
+				return nil, err
+			}
+		}
+		matches = append(matches, Match[E]{Keys: result.Keys, Value: target})
+	}
+
+	return matches, nil
+}
+
 // resultToSlice convert a Result to target slice type.
 func resultToSlice[E any](result Result, typeStrict bool) (target []E, err error) {
 	if result.err != nil {
@@ -966,63 +1486,227 @@ func valueToMap[K comparable, E any](value reflect.Value) map[K]E {
 	return nil
 }
 
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	stringType          = reflect.TypeOf("")
+	mapStringAnyType    = reflect.TypeOf(map[string]any{})
+)
+
+// textUnmarshalers caches whether pointers to map key types implement encoding.TextUnmarshaler, by type.
+var textUnmarshalers sync.Map
+
+// isTextUnmarshaler reports whether a pointer to a type implements encoding.TextUnmarshaler, computed once per type.
+func isTextUnmarshaler(typ reflect.Type) bool {
+	if typ == stringType {
+		return false
+	}
+	if cached, ok := textUnmarshalers.Load(typ); ok {
+		return cached.(bool)
+	}
+
+	implements := reflect.PointerTo(typ).Implements(textUnmarshalerType)
+	textUnmarshalers.Store(typ, implements)
+	return implements
+}
+
 // stringToMapKeyType convert a string key to map key's type.
-func stringToMapKeyType(key string, kind reflect.Type) reflect.Value {
+// Types implementing encoding.TextUnmarshaler are parsed by UnmarshalText, basic kinds are parsed strictly,
+// and composite kinds such as struct, array and pointer are decoded from a JSON literal, such as `{"X":1,"Y":2}`.
+// Interface keys are kept as string. If the key cannot be converted, return error.
+func stringToMapKeyType(key string, kind reflect.Type) (reflect.Value, error) {
 	if !kind.Comparable() {
-		return reflect.ValueOf(key)
+		return reflect.Value{}, fmt.Errorf("key type %s is not comparable", kind)
 	}
 
-	value := reflect.ValueOf(key)
-
 	val := reflect.New(kind)
+	var err error
+
+	switch {
+	case reflect.PointerTo(kind).Implements(textUnmarshalerType):
+		err = val.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key))
+		return val.Elem(), err
+	case kind.Kind() == reflect.Pointer && kind.Implements(textUnmarshalerType):
+		val.Elem().Set(reflect.New(kind.Elem()))
+		err = val.Elem().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key))
+		return val.Elem(), err
+	}
 
 	switch kind.Kind() {
 	case reflect.Bool:
-		val.Elem().SetBool(valueToBool(value))
+		var b bool
+		b, err = strconv.ParseBool(key)
+		val.Elem().SetBool(b)
 	case reflect.String:
-		return value
+		val.Elem().SetString(key)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		val.Elem().SetInt(int64(valueToInt(value)))
+		var i int64
+		i, err = strconv.ParseInt(key, 10, kind.Bits())
+		val.Elem().SetInt(i)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		val.Elem().SetUint(uint64(valueToUint(value)))
+		var u uint64
+		u, err = strconv.ParseUint(key, 10, kind.Bits())
+		val.Elem().SetUint(u)
 	case reflect.Complex64, reflect.Complex128:
-		val.Elem().SetComplex(valueToComplex(value))
+		var c complex128
+		c, err = strconv.ParseComplex(key, kind.Bits())
+		val.Elem().SetComplex(c)
 	case reflect.Float32, reflect.Float64:
-		val.Elem().SetFloat(valueToFloat(value))
+		var f float64
+		f, err = strconv.ParseFloat(key, kind.Bits())
+		val.Elem().SetFloat(f)
+	case reflect.Interface:
+		// The dynamic type is unknown, keep as is
+		return reflect.ValueOf(key), nil
+	default:
+		err = json.Unmarshal([]byte(key), val.Interface())
+	}
+	if err != nil {
+		return reflect.Value{}, err
 	}
 
-	return val.Elem()
+	return val.Elem(), nil
 }
 
-// findStructFieldName try to find a map key by name(first match exactly, then try match caseinsensitive if specified).
+// mapKeyToString convert a map key to string, in the format parsed by stringToMapKeyType.
+func mapKeyToString(value reflect.Value) string {
+	if value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.CanInterface() && value.Type().Implements(textMarshalerType) &&
+		(value.Kind() != reflect.Pointer || !value.IsNil()) {
+		if text, err := value.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Struct, reflect.Array, reflect.Pointer:
+		if data, err := json.Marshal(valueToAny(value)); err == nil {
+			return string(data)
+		}
+	}
+
+	return valueToString(value)
+}
+
+// sortedMapKeys returns keys of a map in a deterministic order.
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return lessValue(keys[i], keys[j])
+	})
+
+	return keys
+}
+
+// lessValue reports whether a sorts before b.
+// Values of basic kinds are compared naturally, others are compared by their string form.
+func lessValue(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+
+	if a.Kind() != b.Kind() {
+		return a.Kind() < b.Kind()
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	default:
+		return valueToString(a) < valueToString(b)
+	}
+}
+
+// findMapKey try to find a map key by name, exactly first, then case-insensitive unless option Case, then normalized
+// with option Normalize. Of keys matched inexactly, the least one in sorted order is returned, or error with option
+// Unambiguous.
 // If not found, return key as is.
-func findMapKeyValue(value reflect.Value, currentKey string, caseSensitive bool) (keyValue reflect.Value) {
-	keyValue = reflect.ValueOf(currentKey)
+func findMapKey(value reflect.Value, currentKey string, opt Option) (reflect.Value, *QueryError) {
+	keyValue := reflect.ValueOf(currentKey)
 
 	// Invalid, return as is
 	if !value.IsValid() || value.IsNil() {
-		return keyValue
+		return keyValue, nil
 	}
 
 	// Map currentKey is not string, convert currentKey to map currentKey's type.
-	if value.Type().Key().Kind() != reflect.String {
-		return stringToMapKeyType(currentKey, value.Type().Key())
+	keyType := value.Type().Key()
+	if keyType.Kind() != reflect.String || isTextUnmarshaler(keyType) {
+		converted, err := stringToMapKeyType(currentKey, keyType)
+		if err != nil {
+			return keyValue, newQueryError(err, ErrNotFound, "[map] invalid key %s for key type %s", currentKey, keyType)
+		}
+		return findMapKeyByValue(value, currentKey, converted), nil
 	}
-
-	// Not caseinsensitive, return as is
-	if caseSensitive {
-		return keyValue
+	if keyType != stringType {
+		keyValue = keyValue.Convert(keyType)
 	}
 
 	// First find currentKey exactly
 	if value.MapIndex(keyValue).IsValid() {
-		return keyValue
+		return keyValue, nil
 	}
 
-	// Then search lower case
-	for _, mapKeyValue := range value.MapKeys() {
-		if strings.TrimSpace(strings.ToLower(mapKeyValue.String())) == strings.TrimSpace(strings.ToLower(currentKey)) {
-			return mapKeyValue
+	// Then search with Unicode case folding unless option Case, then normalized if option Normalize,
+	// independent of map iteration order
+	for _, match := range keyMatchers(opt)[1:] {
+		var found reflect.Value
+		count := 0
+		for _, mapKeyValue := range value.MapKeys() {
+			if !match(mapKeyValue.String(), currentKey) {
+				continue
+			}
+			if count == 0 || lessValue(mapKeyValue, found) {
+				found = mapKeyValue
+			}
+			count++
+		}
+
+		switch {
+		case count == 0:
+			continue
+		case count > 1 && opt&Unambiguous == Unambiguous:
+			return keyValue, newQueryError(nil, ErrAmbiguous, "[map] key %s is ambiguous: %d keys matched", currentKey, count)
+		}
+		return found, nil
+	}
+
+	// Not found, return as is
+	return keyValue, nil
+}
+
+// findMapKeyByValue find a map key equal to a converted key.
+// Pointer keys match by the pointed value, interface keys match by their string form.
+func findMapKeyByValue(value reflect.Value, currentKey string, keyValue reflect.Value) reflect.Value {
+	switch keyValue.Kind() {
+	case reflect.Pointer:
+		for _, mapKeyValue := range sortedMapKeys(value) {
+			if reflect.DeepEqual(valueToAny(mapKeyValue), valueToAny(keyValue)) {
+				return mapKeyValue
+			}
+		}
+	default:
+		if value.Type().Key().Kind() != reflect.Interface || value.MapIndex(keyValue).IsValid() {
+			return keyValue
+		}
+		for _, mapKeyValue := range sortedMapKeys(value) {
+			if mapKeyToString(mapKeyValue) == currentKey {
+				return mapKeyValue
+			}
 		}
 	}
 
@@ -1030,35 +1714,190 @@ func findMapKeyValue(value reflect.Value, currentKey string, caseSensitive bool)
 	return keyValue
 }
 
-// findStructFieldName try to find a struct field by name(first match exactly, then try match caseinsensitive if specified).
-// If not found, return key as is.
-func findStructFieldName(value reflect.Value, currentKey string, caseSensitive bool) string {
-	// Not caseinsensitive, return as is
-	if caseSensitive {
-		return currentKey
+// structFieldIndexKey identifies the field index of a struct type, by the options which affect it.
+type structFieldIndexKey struct {
+	typ      reflect.Type
+	opt      Option
+	tagNames string
+}
+
+// structFieldIndex maps names of the fields of a struct type to the fields found by [findFieldsByDepth], for each key
+// form of [keyForms] and then each name source in order of precedence.
+type structFieldIndex [][]map[string][]reflect.StructField
+
+// structFieldIndexes caches the structFieldIndex of struct types by structFieldIndexKey. It is bounded by the types
+// and options in use, never by the queried keys.
+var structFieldIndexes sync.Map
+
+// findStructField try to find a struct field by key, including fields promoted from embedded structs.
+// Names are matched exactly first, then case-insensitive unless option Case, then normalized with option Normalize.
+// With option Tag, names in the tags are matched before field names. It returns false ok if not found, and error if
+// ambiguous.
+// The Index of the returned field is the index sequence from the struct type, it must not be modified.
+func findStructField(structType reflect.Type, currentKey string, opt Option, tagNames []string) (reflect.StructField, bool, *QueryError) {
+	index := structFieldIndexOf(structType, opt, tagNames)
+	for i, form := range keyForms(opt) {
+		name := form(currentKey)
+		for _, names := range index[i] {
+			fields, ok := names[name]
+			if !ok {
+				continue
+			}
+			if len(fields) > 1 {
+				return fields[0], false, ambiguousFieldError(currentKey, fields)
+			}
+			return fields[0], true, nil
+		}
 	}
 
-	// First find currentKey exactly
-	if value.FieldByName(currentKey).IsValid() {
-		return currentKey
+	return reflect.StructField{}, false, nil
+}
+
+// structFieldIndexOf returns the cached structFieldIndex of a struct type, and builds it on first use.
+func structFieldIndexOf(structType reflect.Type, opt Option, tagNames []string) structFieldIndex {
+	key := structFieldIndexKey{typ: structType, opt: opt & (Case | Tag | Unambiguous | Normalize | tagNamesOnly)}
+	if opt&Tag == Tag {
+		key.tagNames = strings.Join(tagNames, ",")
+	}
+	if cached, ok := structFieldIndexes.Load(key); ok {
+		return cached.(structFieldIndex)
 	}
 
-	// Then search lower case
-	for i := 0; i < value.NumField(); i++ {
-		value.Type().Field(i).IsExported()
-		fieldName := value.Type().Field(i).Name
-		if strings.TrimSpace(strings.ToLower(fieldName)) == strings.TrimSpace(strings.ToLower(currentKey)) {
-			return fieldName
+	index, _ := structFieldIndexes.LoadOrStore(key, buildStructFieldIndex(structType, opt, tagNames))
+	return index.(structFieldIndex)
+}
+
+// buildStructFieldIndex builds the structFieldIndex of a struct type. Every name of the fields, in every key form, is
+// resolved by depth as a key would be, so a lookup of any key costs only map lookups.
+func buildStructFieldIndex(structType reflect.Type, opt Option, tagNames []string) structFieldIndex {
+	// Name sources in order of precedence
+	sources := make([]func(field reflect.StructField) (string, bool), 0, len(tagNames)+1)
+	if opt&Tag == Tag {
+		for _, tagName := range tagNames {
+			tagName := tagName
+			sources = append(sources, func(field reflect.StructField) (string, bool) {
+				name, ok := tagFieldName(field, tagName)
+				return name, ok && name != ""
+			})
 		}
 	}
+	sources = append(sources, func(field reflect.StructField) (string, bool) {
+		if opt&Tag != Tag {
+			return field.Name, true
+		}
+		return field.Name, !isTagIgnored(field, tagNames) && (opt&tagNamesOnly != tagNamesOnly || !hasTagName(field, tagNames))
+	})
 
-	// Not found, return as is
-	return currentKey
+	// Every field at any depth, as a match which never matches visits all of them
+	all := make([]reflect.StructField, 0)
+	findFieldsByDepth(structType, false, func(field reflect.StructField) bool {
+		all = append(all, field)
+		return false
+	})
+
+	forms := keyForms(opt)
+	index := make(structFieldIndex, len(forms))
+	for i, form := range forms {
+		index[i] = make([]map[string][]reflect.StructField, len(sources))
+		for j, source := range sources {
+			form, source := form, source
+			names := make(map[string][]reflect.StructField)
+			for _, field := range all {
+				name, ok := source(field)
+				if !ok {
+					continue
+				}
+				name = form(name)
+				if _, ok := names[name]; ok {
+					continue
+				}
+				names[name] = findFieldsByDepth(structType, opt&Unambiguous == Unambiguous, func(field reflect.StructField) bool {
+					fieldName, ok := source(field)
+					return ok && form(fieldName) == name
+				})
+			}
+			index[i][j] = names
+		}
+	}
+
+	return index
+}
+
+// findFieldsByDepth search fields of a struct type and its embedded structs in breadth-first order, like Go selectors:
+// a shallower field hides deeper ones, and fields of different structs at the same depth are ambiguous.
+// Of fields in the same struct, the first declared one is matched, unless unambiguous is required.
+// It returns the matched fields at the shallowest depth, more than one if ambiguous.
+func findFieldsByDepth(structType reflect.Type, unambiguous bool, match func(field reflect.StructField) bool) []reflect.StructField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	visited := make(map[reflect.Type]bool)
+	for level := []embedded{{typ: structType}}; len(level) > 0; {
+		found := make([]reflect.StructField, 0, 1)
+		next := make([]embedded, 0)
+
+		for _, e := range level {
+			matched := false
+			for i := 0; i < e.typ.NumField(); i++ {
+				field := e.typ.Field(i)
+				field.Index = append(e.index[:len(e.index):len(e.index)], i)
+				if match(field) {
+					if !matched || unambiguous {
+						found = append(found, field)
+					}
+					matched = true
+					continue
+				}
+
+				// Search embedded structs at the next depth, each type only once
+				if field.Anonymous {
+					t := field.Type
+					if t.Kind() == reflect.Pointer {
+						t = t.Elem()
+					}
+					if t.Kind() == reflect.Struct && !visited[t] {
+						next = append(next, embedded{typ: t, index: field.Index})
+					}
+				}
+			}
+		}
+
+		if len(found) > 0 {
+			return found
+		}
+
+		for _, e := range level {
+			visited[e.typ] = true
+		}
+		level = next
+	}
+
+	return nil
+}
+
+// ambiguousFieldError returns the error of a key matching fields at the same depth.
+func ambiguousFieldError(currentKey string, fields []reflect.StructField) *QueryError {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return newQueryError(nil, ErrAmbiguous, "[struct] field %s is ambiguous: %v", currentKey, names)
 }
 
 // pathsToKeys normalized paths to keys by splitting path with comma.
 func pathsToKeys(paths []string) []string {
-	keys := make([]string, 0)
+	// A single path is split in place
+	if len(paths) == 1 {
+		keys := splitWithEscape(paths[0], ",", "\\")
+		for i, key := range keys {
+			keys[i] = strings.TrimSpace(key)
+		}
+		return keys
+	}
+
+	keys := make([]string, 0, len(paths))
 	for _, path := range paths {
 		for _, key := range splitWithEscape(path, ",", "\\") {
 			keys = append(keys, strings.TrimSpace(key))
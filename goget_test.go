@@ -355,7 +355,16 @@ func TestQuery(t *testing.T) {
 
 	assert := assert.New(t)
 	for _, tt := range tests {
-		got, err := query(reflect.ValueOf(tt.value), tt.caseSensitive, tt.safe, tt.keys)
+		opt := None
+		if tt.caseSensitive {
+			opt |= Case
+		}
+		if tt.safe {
+			opt |= Safe
+		}
+		segs, _ := parseKeys(tt.keys)
+
+		got, err := query(reflect.ValueOf(tt.value), opt, defaultTagNames, segs)
 		if tt.err {
 			if err == nil {
 				t.Fatalf("expect error got nil")
@@ -475,117 +484,184 @@ func TestStringToMapKeyType(t *testing.T) {
 	type A struct {
 		A any
 	}
+	type point struct {
+		X, Y int
+	}
 
 	tests := []struct {
 		currentKey string
 		value      any
 		expect     any
 		panic      bool
+		err        bool
 	}{
 		// nil value: panic
-		{"a", nil, nil, true},
-		{"a", new(map[string]any), nil, true},
-		{"a", map[string]any{}, "a", false},
-		{"a", map[int]any{}, 0, false},
-		{"a", map[float32]any{}, float32(0), false},
-		{"a", map[bool]any{}, false, false},
-		{"a", map[uint8]any{}, uint8(0), false},
-		{"a", map[complex128]any{}, complex(0, 0), false},
-		{"a", map[A]any{}, A{}, false},
+		{"a", nil, nil, true, false},
+		{"a", new(map[string]any), nil, true, false},
+		{"a", map[string]any{}, "a", false, false},
+		{"a", map[any]any{}, "a", false, false},
+		// invalid key: error rather than zero key
+		{"a", map[int]any{}, nil, false, true},
+		{"a", map[float32]any{}, nil, false, true},
+		{"a", map[bool]any{}, nil, false, true},
+		{"a", map[uint8]any{}, nil, false, true},
+		{"256", map[uint8]any{}, nil, false, true},
+		{"a", map[complex128]any{}, nil, false, true},
+		{"a", map[A]any{}, nil, false, true},
+		// valid key
+		{"-1", map[int]any{}, -1, false, false},
+		{"1.5", map[float32]any{}, float32(1.5), false, false},
+		{"true", map[bool]any{}, true, false, false},
+		{"255", map[uint8]any{}, uint8(255), false, false},
+		{"(1+2i)", map[complex128]any{}, complex(1, 2), false, false},
+		{`{"A":"a"}`, map[A]any{}, A{"a"}, false, false},
+		{`{"X":1,"Y":2}`, map[point]any{}, point{1, 2}, false, false},
+		{"[1,2]", map[[2]int]any{}, [2]int{1, 2}, false, false},
+		{`{"X":1}`, map[*point]any{}, &point{X: 1}, false, false},
+		{"2006-01-02T15:04:05Z", map[time.Time]any{}, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), false, false},
+		{"2006-01-02", map[time.Time]any{}, nil, false, true},
 	}
 
 	assert := assert.New(t)
 	for _, tt := range tests {
 		if tt.panic {
-			assert.Panics(func() { stringToMapKeyType(tt.currentKey, reflect.ValueOf(tt.value).Type().Key()) })
+			assert.Panics(func() { _, _ = stringToMapKeyType(tt.currentKey, reflect.ValueOf(tt.value).Type().Key()) })
 			continue
 		}
 
-		got := stringToMapKeyType(tt.currentKey, reflect.ValueOf(tt.value).Type().Key())
+		got, err := stringToMapKeyType(tt.currentKey, reflect.ValueOf(tt.value).Type().Key())
+		if tt.err {
+			assert.Errorf(err, "key: %s", tt.currentKey)
+			continue
+		}
+
+		if !assert.NoErrorf(err, "key: %s", tt.currentKey) {
+			continue
+		}
 		assert.Equalf(reflect.ValueOf(tt.expect).Type(), got.Type(), "got: %+v", got)
 		assert.Equalf(reflect.ValueOf(tt.expect).Interface(), got.Interface(), "got: %+v", got)
 	}
 }
 
-func TestFindMapKeyValue(t *testing.T) {
+func TestMapKey(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+
+	start := &point{0, 0}
+	day := time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)
+	obj := map[string]any{
+		"ints":   map[int]string{1: "one", -2: "minus two"},
+		"points": map[point]string{{1, 2}: "a", {3, 4}: "b"},
+		"arrays": map[[2]int]string{{1, 2}: "a"},
+		"ptrs":   map[*point]string{start: "start"},
+		"days":   map[time.Time]string{day: "monday"},
+		"any":    map[any]string{"a": "string", 2: "int", point{5, 6}: "point"},
+	}
+
 	tests := []struct {
-		value         any
-		currentKey    string
-		caseSensitive bool
-		expect        any
-		panic         bool
+		paths []string
+		want  any
+		err   bool
 	}{
-		// nil value: no panic
-		{nil, "", false, "", false},
-		// empty map
-		{map[string]string{}, "", false, "", false},
-		{map[string]string{}, "A", false, "A", false},
-		// empty key
-		{map[string]string{"A": ""}, "", false, "", false},
-		// empty value
-		{map[string]string{}, "A", false, "A", false},
-		// match key case sensitive
-		{map[string]string{"A": ""}, "A", true, "A", false},
-		// match key case insensitive
-		{map[string]string{"A": ""}, "a", false, "A", false},
-		// match key but case not match
-		{map[string]string{"A": ""}, "a", true, "a", false},
-		// not found case sensitive
-		{map[string]string{"A": ""}, "b", true, "b", false},
-		// not found case insensitive
-		{map[string]string{"A": ""}, "b", false, "b", false},
+		{[]string{"ints", "1"}, "one", false},
+		{[]string{"ints", "-2"}, "minus two", false},
+		{[]string{"ints", "a"}, nil, true},
+		{[]string{"ints", "3"}, nil, true},
+		{[]string{"points", `{"X":1\,"Y":2}`}, "a", false},
+		{[]string{"points", `{"Y":4\,"X":3}`}, "b", false},
+		{[]string{"points", `{"X":5}`}, nil, true},
+		{[]string{"points", "{1 2}"}, nil, true},
+		{[]string{"arrays", `[1\,2]`}, "a", false},
+		{[]string{"ptrs", `{"X":0\,"Y":0}`}, "start", false},
+		{[]string{"ptrs", `{"X":1}`}, nil, true},
+		{[]string{"days", "2006-01-02T00:00:00Z"}, "monday", false},
+		{[]string{"days", "monday"}, nil, true},
+		{[]string{"any", "a"}, "string", false},
+		{[]string{"any", "2"}, "int", false},
+		{[]string{"any", `{"X":5\,"Y":6}`}, "point", false},
+		{[]string{"any", "3"}, nil, true},
 	}
 
 	assert := assert.New(t)
 	for _, tt := range tests {
-		if tt.panic {
-			assert.Panics(func() { findMapKeyValue(reflect.ValueOf(tt.value), tt.currentKey, tt.caseSensitive) })
+		got, err := AnyResult(obj, None, tt.paths...)
+		if tt.err {
+			assert.Errorf(err, "paths: %v", tt.paths)
 			continue
 		}
 
-		got := findMapKeyValue(reflect.ValueOf(tt.value), tt.currentKey, tt.caseSensitive)
-		assert.Equalf(tt.expect, got.Interface(), "got: %+v", got)
+		if !assert.NoErrorf(err, "paths: %v", tt.paths) {
+			continue
+		}
+		assert.Equalf(tt.want, got, "paths: %v", tt.paths)
 	}
+
+	// Commas of composite keys in paths are escaped, or use other notations
+	assert.Equal("a", Any(obj, `points,{"X":1\,"Y":2}`))
+	assert.Equal("a", Dot(obj, `points['{"X":1,"Y":2}']`))
+
+	// Error is clear rather than looking up a zero key
+	_, err := AnyResult(obj, None, "ints", "a")
+	assert.ErrorContains(err, "invalid key a for key type int")
+
+	// Keys of matches are in the same format, so they can be queried again
+	matches, err := AllResult[string](obj, None, "points,*")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{
+		{[]string{"points", `{"X":1,"Y":2}`}, "a"},
+		{[]string{"points", `{"X":3,"Y":4}`}, "b"},
+	}, matches)
+	for _, match := range matches {
+		assert.Equal(match.Value, Pointer(obj, "/points/"+match.Keys[1]))
+	}
+	matches, err = AllResult[string](obj, None, "days,*")
+	assert.NoError(err)
+	assert.Equal([]string{"days", "2006-01-02T00:00:00Z"}, matches[0].Keys)
 }
 
-func TestFindStructFieldName(t *testing.T) {
+func TestFindStructField(t *testing.T) {
 	tests := []struct {
-		value         any
-		currentKey    string
-		caseSensitive bool
-		expect        string
-		panic         bool
+		value      any
+		currentKey string
+		opt        Option
+		expect     string
+		panic      bool
 	}{
 		// nil value: panic
-		{nil, "", false, "", true},
+		{nil, "", None, "", true},
 		// empty struct
-		{struct{}{}, "", false, "", false},
-		{struct{}{}, "A", false, "A", false},
+		{struct{}{}, "", None, "", false},
+		{struct{}{}, "A", None, "", false},
 		// empty key
-		{struct{ A string }{""}, "", false, "", false},
+		{struct{ A string }{""}, "", None, "", false},
 		// empty value
-		{struct{ A string }{}, "A", false, "A", false},
+		{struct{ A string }{}, "A", None, "A", false},
 		// match key case sensitive
-		{struct{ A string }{""}, "A", true, "A", false},
+		{struct{ A string }{""}, "A", Case, "A", false},
 		// match key case insensitive
-		{struct{ A string }{""}, "a", false, "A", false},
+		{struct{ A string }{""}, "a", None, "A", false},
 		// match key but case not match
-		{struct{ A string }{""}, "a", true, "a", false},
+		{struct{ A string }{""}, "a", Case, "", false},
 		// not found case sensitive
-		{struct{ A string }{""}, "b", true, "b", false},
+		{struct{ A string }{""}, "b", Case, "", false},
 		// not found case insensitive
-		{struct{ A string }{""}, "b", false, "b", false},
+		{struct{ A string }{""}, "b", None, "", false},
 	}
 
 	assert := assert.New(t)
 	for _, tt := range tests {
 		if tt.panic {
-			assert.Panics(func() { findStructFieldName(reflect.ValueOf(tt.value), tt.currentKey, tt.caseSensitive) })
+			assert.Panics(func() { findStructField(reflect.TypeOf(tt.value), tt.currentKey, tt.opt, defaultTagNames) })
 			continue
 		}
 
-		got := findStructFieldName(reflect.ValueOf(tt.value), tt.currentKey, tt.caseSensitive)
-		assert.Equalf(tt.expect, got, "got: %+v", got)
+		field, ok, err := findStructField(reflect.TypeOf(tt.value), tt.currentKey, tt.opt, defaultTagNames)
+		assert.Nilf(err, "key: %s", tt.currentKey)
+		assert.Equalf(tt.expect != "", ok, "key: %s", tt.currentKey)
+		if ok {
+			assert.Equalf(tt.expect, field.Name, "key: %s", tt.currentKey)
+		}
 	}
 }
 
@@ -706,3 +782,410 @@ func TestAnyToMap(t *testing.T) {
 		assert.Equalf(tt.expect, got, "got: %+v", got)
 	}
 }
+
+func TestAll(t *testing.T) {
+	type address struct {
+		City   string
+		street string
+	}
+	type person struct {
+		Name      string
+		Addresses []address
+		Meta      map[string]any
+	}
+
+	p := &person{
+		Name: "Vin Mars",
+		Addresses: []address{
+			{"Mesa", "123 Main St"},
+			{"Lima", "456 Side St"},
+		},
+		Meta: map[string]any{"b": map[string]any{"city": "Oslo"}, "a": map[string]any{"city": "Rome"}, "c": nil},
+	}
+
+	tests := []struct {
+		value  any
+		opt    Option
+		paths  []string
+		expect []Match[any]
+		err    bool
+	}{
+		{p, None, nil, []Match[any]{{[]string{}, p}}, false},
+		{p, None, []string{"name"}, []Match[any]{{[]string{"Name"}, "Vin Mars"}}, false},
+		{p, None, []string{"Addresses,*,City"}, []Match[any]{
+			{[]string{"Addresses", "0", "City"}, "Mesa"},
+			{[]string{"Addresses", "1", "City"}, "Lima"},
+		}, false},
+		{p, None, []string{"Addresses,*,street"}, []Match[any]{
+			{[]string{"Addresses", "0", "street"}, "123 Main St"},
+			{[]string{"Addresses", "1", "street"}, "456 Side St"},
+		}, false},
+		{p, Safe, []string{"Addresses,*,street"}, nil, true},
+		{p, None, []string{"Addresses,0,*"}, []Match[any]{
+			{[]string{"Addresses", "0", "City"}, "Mesa"},
+			{[]string{"Addresses", "0", "street"}, "123 Main St"},
+		}, false},
+		{p, Safe, []string{"Addresses,0,*"}, []Match[any]{
+			{[]string{"Addresses", "0", "City"}, "Mesa"},
+		}, false},
+		// Map values are walked in order of sorted keys, elements without the key are skipped
+		{p, None, []string{"Meta,*,city"}, []Match[any]{
+			{[]string{"Meta", "a", "city"}, "Rome"},
+			{[]string{"Meta", "b", "city"}, "Oslo"},
+		}, false},
+		{p, None, []string{"Meta,*"}, []Match[any]{
+			{[]string{"Meta", "a"}, map[string]any{"city": "Rome"}},
+			{[]string{"Meta", "b"}, map[string]any{"city": "Oslo"}},
+			{[]string{"Meta", "c"}, nil},
+		}, false},
+		{p, Case, []string{"*,*,City"}, []Match[any]{
+			{[]string{"Addresses", "0", "City"}, "Mesa"},
+			{[]string{"Addresses", "1", "City"}, "Lima"},
+		}, false},
+		{p, None, []string{"Name,*"}, nil, true},
+		{p, None, []string{"Addresses,*,Country"}, nil, true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := AllResult[any](tt.value, tt.opt, tt.paths...)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("expect error got nil: %v", tt.paths)
+			}
+			continue
+		}
+
+		if !assert.Equalf(tt.expect, got, "got: %+v", got) {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	assert.Equal([]string{"Mesa", "Lima"}, All[string](p, "addresses,*,city"))
+	assert.Nil(All[string](p, "addresses,*,country"))
+
+	// Single result getters return the first one
+	assert.Equal("Mesa", String(p, "addresses,*,city"))
+	assert.Equal("Rome", String(p, "meta,*,city"))
+
+	_, err := AllResult[int](p, Type, "addresses,*,city")
+	assert.Error(err)
+
+	// An existing * key takes precedence over the wildcard, and may be escaped
+	stars := map[string]any{"*": "star", "a": "a"}
+	assert.Equal([]string{"star"}, All[string](stars, "*"))
+	assert.Equal([]string{"star"}, All[string](stars, `\*`))
+	assert.Equal("star", String(map[string]any{"m": stars}, `m,\*`))
+	assert.Equal([]string{"a"}, All[string](map[string]any{"m": stars}, "*,a"))
+	assert.Equal([]any{"star", "a"}, JSONPath(stars, "$.*"))
+	assert.Nil(All[string]([]string{"a"}, `\0`))
+
+	// A backslash before other keys is a part of the key
+	escapes := map[string]any{`\n`: "newline", `\`: "backslash", `\*`: "escaped"}
+	assert.Equal("newline", String(escapes, `\n`))
+	assert.Equal("backslash", String(escapes, `\`))
+	assert.Equal("escaped", String(escapes, `\\*`))
+}
+
+func TestLessValue(t *testing.T) {
+	tests := []struct {
+		a, b   any
+		expect bool
+	}{
+		{"a", "b", true},
+		{"b", "a", false},
+		{2, 10, true},
+		{uint8(10), uint8(2), false},
+		{1.5, 2.5, true},
+		{false, true, true},
+		{true, true, false},
+		{1, "a", true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got := lessValue(reflect.ValueOf(tt.a), reflect.ValueOf(tt.b))
+		assert.Equalf(tt.expect, got, "%v < %v", tt.a, tt.b)
+	}
+}
+
+func TestDescent(t *testing.T) {
+	type person struct {
+		Name    string
+		Address any
+		id      int
+	}
+	type address struct {
+		City  string
+		Owner *person
+		id    int
+	}
+
+	p := &person{Name: "Vin Mars", id: 1}
+	p.Address = &address{City: "Mesa", Owner: p, id: 2}
+
+	payload := map[string]any{
+		"id": 1,
+		"b":  []any{map[string]any{"id": 3}, map[string]any{"ID": 4}},
+		"a":  map[string]any{"id": 2, "c": map[string]any{"id": 5}},
+	}
+	// Cyclic map
+	cyclic := map[string]any{"id": 1}
+	cyclic["self"] = cyclic
+
+	tests := []struct {
+		value  any
+		opt    Option
+		paths  []string
+		expect []Match[any]
+		err    bool
+	}{
+		{payload, None, []string{"..,id"}, []Match[any]{
+			{[]string{"id"}, 1},
+			{[]string{"a", "id"}, 2},
+			{[]string{"a", "c", "id"}, 5},
+			{[]string{"b", "0", "id"}, 3},
+			{[]string{"b", "1", "ID"}, 4},
+		}, false},
+		{payload, Case, []string{"..,id"}, []Match[any]{
+			{[]string{"id"}, 1},
+			{[]string{"a", "id"}, 2},
+			{[]string{"a", "c", "id"}, 5},
+			{[]string{"b", "0", "id"}, 3},
+		}, false},
+		{payload, None, []string{"a,..,id"}, []Match[any]{
+			{[]string{"a", "id"}, 2},
+			{[]string{"a", "c", "id"}, 5},
+		}, false},
+		{payload, None, []string{"..,missing"}, nil, true},
+		{p, None, []string{"..,city"}, []Match[any]{
+			{[]string{"Address", "City"}, "Mesa"},
+		}, false},
+		{p, None, []string{"..,id"}, []Match[any]{
+			{[]string{"id"}, 1},
+			{[]string{"Address", "id"}, 2},
+		}, false},
+		{p, Safe, []string{"..,id"}, nil, true},
+		{p, Safe, []string{"..,Name"}, []Match[any]{
+			{[]string{"Name"}, "Vin Mars"},
+		}, false},
+		{cyclic, None, []string{"..,id"}, []Match[any]{
+			{[]string{"id"}, 1},
+		}, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := AllResult[any](tt.value, tt.opt, tt.paths...)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("expect error got nil: %v", tt.paths)
+			}
+			continue
+		}
+
+		if !assert.Equalf(tt.expect, got, "got: %+v", got) {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	assert.Equal("Mesa", String(p, "..,City"))
+	assert.Equal(5, Int(payload, "..,c", "id"))
+
+	// Only .. on its own is descent, other keys starting with .. are map keys
+	dots := map[string]any{"..cfg": 4, "..": map[string]any{"cfg": 5}, "cfg": 6}
+	assert.Equal(4, Int(dots, "..cfg"))
+	assert.Equal(5, Int(dots, `\..,cfg`))
+	assert.Equal([]int{6, 5}, All[int](map[string]any{"dots": dots}, "..,cfg"))
+
+	// An existing .. key takes precedence over the descent
+	assert.Equal([]int{5}, All[int](dots, "..,cfg"))
+}
+
+type embedBase struct {
+	ID   int
+	Name string
+}
+
+type EmbedMeta struct {
+	ID      int
+	Version int
+}
+
+type embedNode struct {
+	*embedNode
+	Value int
+}
+
+func TestEmbedded(t *testing.T) {
+	type withValue struct {
+		embedBase
+		Extra string
+	}
+	type withPointer struct {
+		*embedBase
+	}
+	type shadow struct {
+		embedBase
+		Name string
+	}
+	type conflict struct {
+		embedBase
+		*EmbedMeta
+	}
+	type deep struct {
+		withValue
+		Version int
+	}
+
+	base := embedBase{ID: 1, Name: "base"}
+	obj := map[string]any{
+		"value":    withValue{embedBase: base, Extra: "x"},
+		"pointer":  withPointer{&base},
+		"nil":      withPointer{},
+		"shadow":   shadow{embedBase: base, Name: "outer"},
+		"conflict": conflict{embedBase: base, EmbedMeta: &EmbedMeta{ID: 2, Version: 3}},
+		"deep":     &deep{withValue: withValue{embedBase: base}, Version: 4},
+		"node":     embedNode{&embedNode{Value: 2}, 1},
+	}
+
+	tests := []struct {
+		paths []string
+		opt   Option
+		want  any
+		code  ErrCode
+	}{
+		{[]string{"value", "Name"}, None, "base", 0},
+		{[]string{"value", "name"}, None, "base", 0},
+		{[]string{"value", "name"}, Case, nil, ErrNotFound},
+		{[]string{"value", "id"}, Safe, 1, 0},
+		{[]string{"pointer", "name"}, None, "base", 0},
+		{[]string{"pointer", "Name"}, Case, "base", 0},
+		{[]string{"nil", "name"}, None, nil, ErrNotFound},
+		{[]string{"nil", "embedBase"}, None, (*embedBase)(nil), 0},
+		{[]string{"shadow", "name"}, None, "outer", 0},
+		{[]string{"shadow", "embedBase", "name"}, None, "base", 0},
+		{[]string{"shadow", "embedbase", "name"}, None, "base", 0},
+		{[]string{"shadow", "embedBase"}, Safe, nil, ErrNotFound},
+		{[]string{"conflict", "ID"}, None, nil, ErrAmbiguous},
+		{[]string{"conflict", "id"}, None, nil, ErrAmbiguous},
+		{[]string{"conflict", "version"}, None, 3, 0},
+		{[]string{"conflict", "EmbedMeta", "id"}, Safe, 2, 0},
+		{[]string{"conflict", "embedBase", "id"}, None, 1, 0},
+		{[]string{"deep", "name"}, None, "base", 0},
+		{[]string{"deep", "version"}, None, 4, 0},
+		{[]string{"deep", "withValue", "extra"}, None, "", 0},
+		{[]string{"node", "value"}, None, 1, 0},
+		{[]string{"node", "embedNode", "value"}, None, 2, 0},
+		{[]string{"node", "missing"}, None, nil, ErrNotFound},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := AnyResult(obj, tt.opt, tt.paths...)
+		if tt.code != 0 {
+			var queryErr *QueryError
+			if assert.ErrorAsf(err, &queryErr, "paths: %v", tt.paths) {
+				assert.Equalf(tt.code, queryErr.Code, "paths: %v", tt.paths)
+			}
+			continue
+		}
+
+		if !assert.NoErrorf(err, "paths: %v", tt.paths) {
+			continue
+		}
+		assert.Equalf(tt.want, got, "paths: %v", tt.paths)
+	}
+
+	matches, err := AllResult[int](obj, None, "deep,id")
+	assert.NoError(err)
+	assert.Equal([]Match[int]{{[]string{"deep", "ID"}, 1}}, matches)
+}
+
+func TestFindMapKey(t *testing.T) {
+	type name string
+
+	tests := []struct {
+		value      any
+		currentKey string
+		opt        Option
+		expect     any
+		code       ErrCode
+	}{
+		// nil value: no panic
+		{nil, "", None, "", 0},
+		// empty map
+		{map[string]string{}, "", None, "", 0},
+		{map[string]string{}, "A", None, "A", 0},
+		// empty key
+		{map[string]string{"A": ""}, "", None, "", 0},
+		// match key case sensitive
+		{map[string]string{"A": ""}, "A", Case, "A", 0},
+		// match key case insensitive
+		{map[string]string{"A": ""}, "a", None, "A", 0},
+		// match key but case not match
+		{map[string]string{"A": ""}, "a", Case, "a", 0},
+		// not found case sensitive
+		{map[string]string{"A": ""}, "b", Case, "b", 0},
+		// not found case insensitive
+		{map[string]string{"A": ""}, "b", None, "b", 0},
+		// precedence of exact and case-insensitive matches
+		{map[string]int{"Name": 1, "NAME": 2, "name": 3}, "name", None, "name", 0},
+		{map[string]int{"Name": 1, "NAME": 2, "name": 3}, "nAmE", None, "NAME", 0},
+		{map[string]int{"Name": 1, "NAME": 2}, "nAmE", Case, "nAmE", 0},
+		{map[string]int{"Name": 1, "NAME": 2}, "nAmE", Unambiguous, nil, ErrAmbiguous},
+		{map[string]int{"Name": 1, "NAME": 2}, "Name", Unambiguous, "Name", 0},
+		{map[string]int{"Name": 1}, "nAmE", Unambiguous, "Name", 0},
+		{map[string]int{" Name ": 1}, "name", None, " Name ", 0},
+		// Unicode folding
+		{map[string]int{"ÉTÉ": 1}, "été", None, "ÉTÉ", 0},
+		{map[string]int{"\u212a": 1}, "k", None, "\u212a", 0},
+		{map[string]int{"Σίσυφος": 1}, "ΣΊΣΥΦΟΣ", None, "Σίσυφος", 0},
+		// Named string type
+		{map[name]int{"Name": 1}, "name", None, name("Name"), 0},
+		{map[name]int{"Name": 1}, "other", None, name("other"), 0},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		// Map iteration order is random, the result must be stable
+		for i := 0; i < 20; i++ {
+			got, err := findMapKey(reflect.ValueOf(tt.value), tt.currentKey, tt.opt)
+			if tt.code != 0 {
+				if assert.NotNilf(err, "key: %s", tt.currentKey) {
+					assert.Equalf(tt.code, err.Code, "key: %s", tt.currentKey)
+				}
+				break
+			}
+
+			assert.Nilf(err, "key: %s", tt.currentKey)
+			assert.Equalf(tt.expect, got.Interface(), "key: %s", tt.currentKey)
+		}
+	}
+}
+
+func TestUnambiguous(t *testing.T) {
+	type collide struct {
+		Name string
+		NAME string
+	}
+
+	obj := map[string]any{
+		"user": collide{"a", "b"},
+		"meta": map[string]int{"ID": 1, "id": 2},
+	}
+
+	assert := assert.New(t)
+
+	assert.Equal("a", MayString(obj, None, "user", "name"))
+	assert.Equal(1, MayInt(obj, None, "meta", "Id"))
+	assert.Equal(2, MayInt(obj, Unambiguous, "meta", "id"))
+
+	for _, paths := range [][]string{{"user", "name"}, {"meta", "Id"}} {
+		_, err := AnyResult(obj, Unambiguous, paths...)
+		var queryErr *QueryError
+		if assert.ErrorAsf(err, &queryErr, "paths: %v", paths) {
+			assert.Equalf(ErrAmbiguous, queryErr.Code, "paths: %v", paths)
+		}
+	}
+}
@@ -0,0 +1,871 @@
+package goget
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// jsonPathOption evaluates JSONPath expressions like RFC 9535: names are case-sensitive and filters compare strictly.
+const jsonPathOption = Case | Type
+
+// JSONPath like [All], but queries by a JSONPath expression (RFC 9535), returns all result elements.
+// Nil is returned if the expression is invalid or no element is found.
+func JSONPath(obj any, expr string) []any {
+	matches, err := JSONPathResult[any](obj, None, expr)
+	if err != nil || len(matches) == 0 {
+		return nil
+	}
+
+	values := make([]any, len(matches))
+	for i, match := range matches {
+		values[i] = match.Value
+	}
+
+	return values
+}
+
+// JSONPathResult like [AllResult], but queries by a JSONPath expression (RFC 9535).
+// The expression is always evaluated with options Case and Type as specified by RFC 9535: names are case-sensitive,
+// filters compare values of different types as not equal, and index selectors never select object members. Other
+// options apply as usual, and Type of opt also applies to the conversion of result elements.
+// An expression which selects no element results in an empty slice and nil error, like an empty nodelist.
+func JSONPathResult[E any](obj any, opt Option, expr string) (_ []Match[E], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	segs, parseErr := parseJSONPath(expr)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	results, queryErr := queryAll(obj, opt|jsonPathOption, defaultTagNames, segs)
+	if queryErr != nil {
+		if queryErr.Code == ErrNotFound {
+			return []Match[E]{}, nil
+		}
+		return nil, queryErr
+	}
+
+	return resultsToMatches[E](results, opt&Type == Type)
+}
+
+// CompileJSONPath parses a JSONPath expression (RFC 9535) into a [Path] and reports syntax errors.
+// The typed methods of the Path return the first result element, evaluated like [JSONPathResult].
+func CompileJSONPath(expr string) (*Path, error) {
+	segs, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Path{segs: segs, opt: jsonPathOption, tagNames: defaultTagNames}, nil
+}
+
+// MustCompileJSONPath like [CompileJSONPath], but panics on error.
+func MustCompileJSONPath(expr string) *Path {
+	p, err := CompileJSONPath(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// jsonPathParser is a recursive descent parser of JSONPath expressions:
+//
+//	query      = "$" segments
+//	segments   = *( "." name | ".*" | ".." ( name | "*" | bracket ) | bracket )
+//	bracket    = "[" selector *( "," selector ) "]"
+//	selector   = 'name' | * | index | start:end:step | ?or
+//	or         = and *( "||" and )
+//	and        = basic *( "&&" basic )
+//	basic      = [ "!" ] "(" or ")" | [ "!" ] test | comparable op comparable
+//	comparable = literal | @segments | $segments | function "(" args ")"
+type jsonPathParser struct {
+	scanner
+}
+
+// parseJSONPath parses a JSONPath expression to segments.
+func parseJSONPath(expr string) ([]segment, *QueryError) {
+	p := &jsonPathParser{scanner{notation: "jsonpath", s: expr}}
+	if !p.consume("$") {
+		return nil, p.errorf("must start with $")
+	}
+
+	segs, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+
+	return segs, nil
+}
+
+// consume skips token if it is next.
+func (p *jsonPathParser) consume(token string) bool {
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+// skipBlank skips blank spaces.
+func (p *jsonPathParser) skipBlank() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\r", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// parseSegments parses segments until no segment follows.
+func (p *jsonPathParser) parseSegments() ([]segment, *QueryError) {
+	segs := make([]segment, 0)
+	for {
+		start := p.pos
+		p.skipBlank()
+
+		switch {
+		case p.consume(".."):
+			segs = append(segs, segment{kind: descentSegment, key: ".."})
+			if p.pos < len(p.s) && p.s[p.pos] == '[' {
+				seg, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				segs = append(segs, seg)
+				continue
+			}
+			fallthrough
+
+		case p.consume("."):
+			if p.consume("*") {
+				segs = append(segs, segment{kind: wildcardSegment, key: "*"})
+				continue
+			}
+			name, ok := p.parseName()
+			if !ok {
+				return nil, p.errorf("invalid member name")
+			}
+			segs = append(segs, segment{key: name})
+
+		case p.pos < len(p.s) && p.s[p.pos] == '[':
+			seg, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segs = append(segs, seg)
+
+		default:
+			p.pos = start
+			return segs, nil
+		}
+	}
+}
+
+// parseName parses a member name shorthand: letters, digits and underscores, not starting with a digit.
+func (p *jsonPathParser) parseName() (string, bool) {
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		isFirst := r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || r >= utf8.RuneSelf
+		if !isFirst && (p.pos == start || r < '0' || r > '9') {
+			break
+		}
+		p.pos += size
+	}
+
+	return p.s[start:p.pos], p.pos > start
+}
+
+// parseBracket parses selectors in brackets, multiple selectors make a union.
+func (p *jsonPathParser) parseBracket() (segment, *QueryError) {
+	start := p.pos
+	p.pos++ // [
+
+	alts := make([]segment, 0, 1)
+	for {
+		p.skipBlank()
+		seg, err := p.parseSelector()
+		if err != nil {
+			return seg, err
+		}
+		alts = append(alts, seg)
+
+		p.skipBlank()
+		if p.consume("]") {
+			break
+		}
+		if !p.consume(",") {
+			return seg, p.errorf("expect , or ]")
+		}
+	}
+
+	if len(alts) == 1 {
+		return alts[0], nil
+	}
+	return segment{kind: unionSegment, key: p.s[start:p.pos], union: alts}, nil
+}
+
+// parseSelector parses a selector in brackets: name, wildcard, index, slice or filter.
+func (p *jsonPathParser) parseSelector() (segment, *QueryError) {
+	start := p.pos
+	if p.pos >= len(p.s) {
+		return segment{}, p.errorf("unterminated brackets")
+	}
+
+	switch c := p.s[p.pos]; {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return segment{}, err
+		}
+		return segment{key: name}, nil
+
+	case c == '*':
+		p.pos++
+		return segment{kind: wildcardSegment, key: "*"}, nil
+
+	case c == '?':
+		p.pos++
+		f, err := p.parseOr()
+		if err != nil {
+			return segment{}, err
+		}
+		return segment{kind: filterSegment, key: p.s[start:p.pos], filter: f, every: true}, nil
+	}
+
+	// Index or slice
+	var bounds [3]int
+	var has [3]bool
+	for i := 0; i < 3; i++ {
+		p.skipBlank()
+		n, ok, err := p.parseInt()
+		if err != nil {
+			return segment{}, err
+		}
+		if ok {
+			bounds[i], has[i] = n, true
+		}
+		p.skipBlank()
+		if i == 2 || !p.consume(":") {
+			if i == 0 {
+				if !has[0] {
+					return segment{}, p.errorf("invalid selector")
+				}
+				key := strconv.Itoa(bounds[0])
+				return segment{key: key, index: bounds[0], isIndex: true, strict: true}, nil
+			}
+			break
+		}
+	}
+
+	rng := &sliceRange{start: bounds[0], hasStart: has[0], end: bounds[1], hasEnd: has[1], step: 1}
+	if has[2] {
+		rng.step = bounds[2]
+	}
+	return segment{kind: rangeSegment, key: strings.TrimSpace(p.s[start:p.pos]), rng: rng, every: true}, nil
+}
+
+// parseInt parses an integer, returns false ok if there is none. Leading zeros and -0 are invalid.
+func (p *jsonPathParser) parseInt() (int, bool, *QueryError) {
+	start := p.pos
+	if p.pos < len(p.s) && p.s[p.pos] == '-' {
+		p.pos++
+	}
+	digits := p.pos
+	for p.pos < len(p.s) && '0' <= p.s[p.pos] && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+
+	text := p.s[start:p.pos]
+	if p.pos > digits && p.s[digits] == '0' && (p.pos-digits > 1 || digits > start) {
+		p.pos = start
+		return 0, false, p.errorf("invalid integer %s", text)
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		p.pos = start
+		return 0, false, nil
+	}
+	return n, true, nil
+}
+
+// parseString parses a string literal in single or double quotes with JSON escapes.
+func (p *jsonPathParser) parseString() (string, *QueryError) {
+	quote := p.s[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c != '\\':
+			b.WriteByte(c)
+		case p.pos >= len(p.s):
+			return "", p.errorf("unterminated string")
+		default:
+			e := p.s[p.pos]
+			p.pos++
+			switch e {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '/', '\\', '\'', '"':
+				b.WriteByte(e)
+			case 'u':
+				r, err := p.parseUnicode()
+				if err != nil {
+					return "", err
+				}
+				b.WriteRune(r)
+			default:
+				return "", p.errorf("invalid escape \\%c", e)
+			}
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+// parseUnicode parses the hex digits of a \u escape, including a surrogate pair.
+func (p *jsonPathParser) parseUnicode() (rune, *QueryError) {
+	hex := func() (rune, bool) {
+		if p.pos+4 > len(p.s) {
+			return 0, false
+		}
+		n, err := strconv.ParseUint(p.s[p.pos:p.pos+4], 16, 16)
+		if err != nil {
+			return 0, false
+		}
+		p.pos += 4
+		return rune(n), true
+	}
+
+	r, ok := hex()
+	if !ok {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	if 0xD800 <= r && r < 0xDC00 {
+		if !p.consume(`\u`) {
+			return 0, p.errorf("invalid surrogate pair")
+		}
+		low, ok := hex()
+		if !ok || low < 0xDC00 || low > 0xDFFF {
+			return 0, p.errorf("invalid surrogate pair")
+		}
+		r = (r-0xD800)<<10 + (low - 0xDC00) + 0x10000
+	}
+
+	return r, nil
+}
+
+// parseOr parses logical or expression.
+func (p *jsonPathParser) parseOr() (filter, *QueryError) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipBlank()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalOr{left: left, right: right}
+	}
+}
+
+// parseAnd parses logical and expression.
+func (p *jsonPathParser) parseAnd() (filter, *QueryError) {
+	left, err := p.parseBasic()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipBlank()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalAnd{left: left, right: right}
+	}
+}
+
+// jsonPathCompareTokens are tokens of comparison operators in JSONPath, longer tokens must be matched first.
+var jsonPathCompareTokens = []struct {
+	token string
+	op    compareOp
+}{
+	{"==", opEq}, {"!=", opNe}, {"<=", opLe}, {">=", opGe}, {"<", opLt}, {">", opGt},
+}
+
+// parseBasic parses parenthesized, negated, test or comparison expression.
+func (p *jsonPathParser) parseBasic() (filter, *QueryError) {
+	p.skipBlank()
+
+	if p.consume("!") {
+		p.skipBlank()
+		if p.consume("(") {
+			f, err := p.parseParen()
+			if err != nil {
+				return nil, err
+			}
+			return &logicalNot{operand: f}, nil
+		}
+
+		operand, err := p.parseComparable()
+		if err != nil {
+			return nil, err
+		}
+		test, err := p.testOf(operand)
+		if err != nil {
+			return nil, err
+		}
+		return &logicalNot{operand: test}, nil
+	}
+
+	if p.consume("(") {
+		return p.parseParen()
+	}
+
+	left, err := p.parseComparable()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipBlank()
+	for _, t := range jsonPathCompareTokens {
+		if p.consume(t.token) {
+			if err := p.checkComparable(left, t.token); err != nil {
+				return nil, err
+			}
+			right, err := p.parseComparable()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.checkComparable(right, t.token); err != nil {
+				return nil, err
+			}
+			return &jsonPathComparison{left: left, op: t.op, right: right}, nil
+		}
+	}
+
+	return p.testOf(left)
+}
+
+// parseParen parses expression in parentheses after "(".
+func (p *jsonPathParser) parseParen() (filter, *QueryError) {
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipBlank()
+	if !p.consume(")") {
+		return nil, p.errorf("unbalanced parentheses")
+	}
+	return f, nil
+}
+
+// checkComparable checks an operand of a comparison by op, which must be a value (RFC 9535 section 2.4.3): a literal,
+// a singular query, or a function returning a value rather than a logical result like match().
+func (p *jsonPathParser) checkComparable(operand *jsonPathOperand, op string) *QueryError {
+	switch {
+	case !operand.isSingular():
+		return p.errorf("expect singular query in comparison %s", op)
+	case operand.fn == "match" || operand.fn == "search":
+		return p.errorf("%s() is not comparable by %s", operand.fn, op)
+	}
+	return nil
+}
+
+// testOf returns the test expression of an operand, which must be a query or a logical function.
+func (p *jsonPathParser) testOf(operand *jsonPathOperand) (filter, *QueryError) {
+	if operand.query == nil && operand.fn != "match" && operand.fn != "search" {
+		return nil, p.errorf("expect comparison")
+	}
+	return &jsonPathTest{operand: operand}, nil
+}
+
+// jsonPathFunctions are the function extensions and their arities.
+var jsonPathFunctions = map[string]int{
+	"length": 1,
+	"count":  1,
+	"match":  2,
+	"search": 2,
+	"value":  1,
+}
+
+// parseComparable parses a literal, a query or a function call.
+func (p *jsonPathParser) parseComparable() (*jsonPathOperand, *QueryError) {
+	p.skipBlank()
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("expect operand")
+	}
+
+	switch c := p.s[p.pos]; {
+	case c == '@' || c == '$':
+		p.pos++
+		segs, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathOperand{query: segs, absolute: c == '$'}, nil
+
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathOperand{literal: reflect.ValueOf(s)}, nil
+
+	case c == '-' || ('0' <= c && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.s) && strings.IndexByte("0123456789.eE+-", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		text := p.s[start:p.pos]
+		if !numberRegexp.MatchString(text) {
+			return nil, p.errorf("invalid number %s", text)
+		}
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return &jsonPathOperand{literal: reflect.ValueOf(n)}, nil
+		}
+		f, _ := strconv.ParseFloat(text, 64)
+		return &jsonPathOperand{literal: reflect.ValueOf(f)}, nil
+	}
+
+	name, _ := p.parseName()
+	switch name {
+	case "true", "false":
+		return &jsonPathOperand{literal: reflect.ValueOf(name == "true")}, nil
+	case "null":
+		return &jsonPathOperand{literal: reflect.Zero(reflect.TypeOf((*any)(nil)).Elem())}, nil
+	}
+
+	arity, ok := jsonPathFunctions[name]
+	if !ok || !p.consume("(") {
+		return nil, p.errorf("invalid operand %s", name)
+	}
+
+	fn := &jsonPathOperand{fn: name}
+	for {
+		arg, err := p.parseComparable()
+		if err != nil {
+			return nil, err
+		}
+		fn.args = append(fn.args, arg)
+
+		p.skipBlank()
+		if p.consume(")") {
+			break
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expect , or )")
+		}
+	}
+	if len(fn.args) != arity {
+		return nil, p.errorf("%s() takes %d arguments", name, arity)
+	}
+
+	// Compile the constant pattern once
+	if arity == 2 && fn.args[1].literal.Kind() == reflect.String {
+		re, err := compileJSONPathRegexp(fn.args[1].literal.String(), name == "match")
+		if err != nil {
+			return nil, p.errorf("invalid pattern: %v", err)
+		}
+		fn.re = re
+	}
+
+	return fn, nil
+}
+
+// compileJSONPathRegexp compiles the pattern of match() which matches the entire string, or search().
+func compileJSONPathRegexp(pattern string, entire bool) (*regexp.Regexp, error) {
+	if entire {
+		pattern = `^(?:` + pattern + `)$`
+	}
+	return regexp.Compile(pattern)
+}
+
+// jsonPathOperand is a literal, a query or a function call in JSONPath filters.
+type jsonPathOperand struct {
+	literal  reflect.Value
+	query    []segment // non-nil for a query
+	absolute bool      // query from the root $, otherwise from the current element @
+	fn       string
+	args     []*jsonPathOperand
+	re       *regexp.Regexp // compiled constant pattern of match() and search()
+}
+
+// isSingular reports whether the operand is not a query, or a singular query of names and indexes only,
+// which selects at most one element.
+func (o *jsonPathOperand) isSingular() bool {
+	for _, seg := range o.query {
+		if seg.kind != keySegment {
+			return false
+		}
+	}
+	return true
+}
+
+// eval returns the result elements of the operand on the current element.
+func (o *jsonPathOperand) eval(elem reflect.Value, w *walker) []reflect.Value {
+	switch {
+	case o.query != nil:
+		if o.absolute {
+			elem = w.root
+		}
+		results := make([]reflect.Value, 0)
+		sub := &walker{opt: w.opt, tagNames: w.tagNames, root: w.root, visit: func(v reflect.Value, _ []string) bool {
+			results = append(results, v)
+			return true
+		}}
+		sub.walk(elem, o.query, nil)
+		return results
+
+	case o.fn != "":
+		if v, ok := o.call(elem, w); ok {
+			return []reflect.Value{v}
+		}
+		return nil
+
+	default:
+		return []reflect.Value{o.literal}
+	}
+}
+
+// single returns the only result element of the operand, or an invalid Value if there is not exactly one.
+func (o *jsonPathOperand) single(elem reflect.Value, w *walker) reflect.Value {
+	results := o.eval(elem, w)
+	if len(results) != 1 {
+		return reflect.Value{}
+	}
+
+	value, err := toConcreteElem(results[0], w.opt&Safe == Safe, 0)
+	if err != nil || !value.IsValid() {
+		return reflect.Zero(reflect.TypeOf((*any)(nil)).Elem())
+	}
+	return value
+}
+
+// call calls the function, returns false ok if the result is nothing.
+func (o *jsonPathOperand) call(elem reflect.Value, w *walker) (reflect.Value, bool) {
+	switch o.fn {
+	case "length":
+		value := o.args[0].single(elem, w)
+		switch value.Kind() {
+		case reflect.String:
+			return reflect.ValueOf(utf8.RuneCountInString(value.String())), true
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return reflect.ValueOf(value.Len()), true
+		case reflect.Struct:
+			// Count the members which queries can select, as for count(@.*)
+			n := 0
+			eachChild(value, w.opt, w.tagNames, func(reflect.Value, string) bool {
+				n++
+				return false
+			})
+			return reflect.ValueOf(n), true
+		}
+		return reflect.Value{}, false
+
+	case "count":
+		return reflect.ValueOf(len(o.args[0].eval(elem, w))), true
+
+	case "value":
+		results := o.args[0].eval(elem, w)
+		if len(results) != 1 {
+			return reflect.Value{}, false
+		}
+		return results[0], true
+
+	default: // match, search
+		value := o.args[0].single(elem, w)
+		if value.Kind() != reflect.String {
+			return reflect.ValueOf(false), true
+		}
+
+		re := o.re
+		if re == nil {
+			pattern := o.args[1].single(elem, w)
+			if pattern.Kind() != reflect.String {
+				return reflect.ValueOf(false), true
+			}
+			var err error
+			if re, err = compileJSONPathRegexp(pattern.String(), o.fn == "match"); err != nil {
+				return reflect.ValueOf(false), true
+			}
+		}
+		return reflect.ValueOf(re.MatchString(value.String())), true
+	}
+}
+
+// jsonPathTest is an existence test of a query, or a logical function call.
+type jsonPathTest struct {
+	operand *jsonPathOperand
+}
+
+func (f *jsonPathTest) match(elem reflect.Value, w *walker) bool {
+	if f.operand.query != nil {
+		return len(f.operand.eval(elem, w)) > 0
+	}
+
+	result, ok := f.operand.call(elem, w)
+	return ok && result.Kind() == reflect.Bool && result.Bool()
+}
+
+// jsonPathComparison compares two operands in JSONPath filters.
+type jsonPathComparison struct {
+	left  *jsonPathOperand
+	op    compareOp
+	right *jsonPathOperand
+}
+
+func (f *jsonPathComparison) match(elem reflect.Value, w *walker) bool {
+	left, right := f.left.single(elem, w), f.right.single(elem, w)
+
+	// Nothing only equals to nothing
+	if !left.IsValid() || !right.IsValid() {
+		equal := !left.IsValid() && !right.IsValid()
+		switch f.op {
+		case opEq, opLe, opGe:
+			return equal
+		case opNe:
+			return !equal
+		}
+		return false
+	}
+
+	c, ordered, ok := compareValues(left, right, w.opt&Type == Type)
+	switch {
+	case !ok:
+		return f.op == opNe
+	case !ordered:
+		switch f.op {
+		case opEq, opLe, opGe:
+			return c == 0
+		case opNe:
+			return c != 0
+		}
+		return false
+	}
+
+	switch f.op {
+	case opEq:
+		return c == 0
+	case opNe:
+		return c != 0
+	case opLt:
+		return c < 0
+	case opLe:
+		return c <= 0
+	case opGt:
+		return c > 0
+	case opGe:
+		return c >= 0
+	}
+
+	return false
+}
+
+// compareValues compares two concret values, returns -1, 0 or +1, and false ordered if only equality is defined.
+// It returns false ok if values are not comparable.
+// Numbers are compared numerically, strings lexically and time.Time chronologically, other values are deeply equal or not.
+// Unless typeStrict, a number is also comparable to a string in number format.
+func compareValues(a, b reflect.Value, typeStrict bool) (c int, ordered bool, ok bool) {
+	if isNilValue(a) || isNilValue(b) {
+		if isNilValue(a) && isNilValue(b) {
+			return 0, false, true
+		}
+		return 1, false, true
+	}
+
+	// Time to time or string
+	ta, aIsTime := valueToAny(a).(time.Time)
+	tb, bIsTime := valueToAny(b).(time.Time)
+	switch {
+	case aIsTime && b.Kind() == reflect.String:
+		tb, bIsTime = parseTime(b.String())
+	case bIsTime && a.Kind() == reflect.String:
+		ta, aIsTime = parseTime(a.String())
+	}
+	if aIsTime && bIsTime {
+		switch {
+		case ta.Before(tb):
+			return -1, true, true
+		case ta.After(tb):
+			return 1, true, true
+		}
+		return 0, true, true
+	}
+
+	aIsNumber, bIsNumber := isNumberKind(a.Kind()), isNumberKind(b.Kind())
+	switch {
+	case aIsNumber && bIsNumber:
+		c, ok := compareNumbers(valueToString(a), valueToString(b))
+		return c, true, ok
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), true, true
+	case !typeStrict && (aIsNumber && b.Kind() == reflect.String || bIsNumber && a.Kind() == reflect.String):
+		c, ok := compareNumbers(valueToString(a), valueToString(b))
+		return c, true, ok
+	case a.Kind() != b.Kind():
+		return 0, false, false
+	}
+
+	if reflect.DeepEqual(valueToAny(a), valueToAny(b)) {
+		return 0, false, true
+	}
+	return 1, false, true
+}
+
+// parseTime parses a string in RFC3339, 2006-01-02 15:04:05 or 2006-01-02.
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isNumberKind reports whether kind is an integer or float kind.
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
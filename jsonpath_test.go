@@ -0,0 +1,255 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		expr  string
+		kinds []segmentKind
+		keys  []string
+		err   bool
+	}{
+		{"$", []segmentKind{}, []string{}, false},
+		{"$.a.b", []segmentKind{keySegment, keySegment}, []string{"a", "b"}, false},
+		{"$['a']['b,c']", []segmentKind{keySegment, keySegment}, []string{"a", "b,c"}, false},
+		{`$["a\"b"]['é']`, []segmentKind{keySegment, keySegment}, []string{`a"b`, "é"}, false},
+		{"$[0][-1]", []segmentKind{keySegment, keySegment}, []string{"0", "-1"}, false},
+		{"$.*[*]", []segmentKind{wildcardSegment, wildcardSegment}, []string{"*", "*"}, false},
+		{"$..a", []segmentKind{descentSegment, keySegment}, []string{"..", "a"}, false},
+		{"$..*", []segmentKind{descentSegment, wildcardSegment}, []string{"..", "*"}, false},
+		{"$..[0]", []segmentKind{descentSegment, keySegment}, []string{"..", "0"}, false},
+		{"$[1:3]", []segmentKind{rangeSegment}, []string{"1:3"}, false},
+		{"$[::-1]", []segmentKind{rangeSegment}, []string{"::-1"}, false},
+		{"$[0, 'a']", []segmentKind{unionSegment}, []string{"[0, 'a']"}, false},
+		{"$[?@.a > 1]", []segmentKind{filterSegment}, []string{"?@.a > 1"}, false},
+		{"$ .a [ 0 ]", []segmentKind{keySegment, keySegment}, []string{"a", "0"}, false},
+		{"a.b", nil, nil, true},
+		{"$.", nil, nil, true},
+		{"$.1a", nil, nil, true},
+		{"$[", nil, nil, true},
+		{"$[a]", nil, nil, true},
+		{"$['a'", nil, nil, true},
+		{"$['a]", nil, nil, true},
+		{`$['\x']`, nil, nil, true},
+		{"$[0:1:2:3]", nil, nil, true},
+		{"$[?@.a]x", nil, nil, true},
+		{"$[?1]", nil, nil, true},
+		{"$[?@.a ==]", nil, nil, true},
+		{"$[?(@.a == 1]", nil, nil, true},
+		{"$[?foo(@.a)]", nil, nil, true},
+		{"$[?length(@.a, 1) == 1]", nil, nil, true},
+		{"$[?count(@.*) > 1]", []segmentKind{filterSegment}, []string{"?count(@.*) > 1"}, false},
+		{"$[?match(@.a, '(')]", nil, nil, true},
+		{"$[?length(@.a)]", nil, nil, true},
+		{"$[0][10][-10]", []segmentKind{keySegment, keySegment, keySegment}, []string{"0", "10", "-10"}, false},
+		{"$[01]", nil, nil, true},
+		{"$[-0]", nil, nil, true},
+		{"$[00:1]", nil, nil, true},
+		{"$[1:-0]", nil, nil, true},
+		{"$[::01]", nil, nil, true},
+		{"$[?@.a[0]['b'] == $.c]", []segmentKind{filterSegment}, []string{"?@.a[0]['b'] == $.c"}, false},
+		{"$[?@.* == 1]", nil, nil, true},
+		{"$[?1 == @..a]", nil, nil, true},
+		{"$[?@.a[0:1] == 1]", nil, nil, true},
+		{"$[?@[0, 1] == 1]", nil, nil, true},
+		{"$[?@[?@.a] == 1]", nil, nil, true},
+		{"$[?match(@.a, 'x') == true]", nil, nil, true},
+		{"$[?false != search(@.a, 'x')]", nil, nil, true},
+		{"$[?length(@.a) == count(@.*)]", []segmentKind{filterSegment}, []string{"?length(@.a) == count(@.*)"}, false},
+		{"$[?match(@.a, 'x') && value(@.b) == 1]", []segmentKind{filterSegment}, []string{"?match(@.a, 'x') && value(@.b) == 1"}, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		segs, err := parseJSONPath(tt.expr)
+		if tt.err {
+			if assert.NotNilf(err, "expr: %s", tt.expr) {
+				assert.Equalf(ErrSyntax, err.Code, "expr: %s", tt.expr)
+			}
+			continue
+		}
+
+		if !assert.Nilf(err, "expr: %s", tt.expr) {
+			continue
+		}
+		kinds := make([]segmentKind, len(segs))
+		for i, seg := range segs {
+			kinds[i] = seg.kind
+		}
+		assert.Equalf(tt.kinds, kinds, "expr: %s", tt.expr)
+		assert.Equalf(tt.keys, segmentsToKeys(segs), "expr: %s", tt.expr)
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	type book struct {
+		Category string  `json:"category"`
+		Author   string  `json:"author"`
+		Title    string  `json:"title"`
+		ISBN     string  `json:"isbn"`
+		Price    float64 `json:"price"`
+	}
+	type bicycle struct {
+		Color string  `json:"color"`
+		Price float64 `json:"price"`
+	}
+	type store struct {
+		Book    []book  `json:"book"`
+		Bicycle bicycle `json:"bicycle"`
+	}
+
+	obj := map[string]any{
+		"store": store{
+			Book: []book{
+				{"reference", "Nigel Rees", "Sayings of the Century", "", 8.95},
+				{"fiction", "Evelyn Waugh", "Sword of Honour", "", 12.99},
+				{"fiction", "Herman Melville", "Moby Dick", "0-553-21311-3", 8.99},
+				{"fiction", "J. R. R. Tolkien", "The Lord of the Rings", "0-395-19395-8", 22.99},
+			},
+			Bicycle: bicycle{"red", 399},
+		},
+		"expensive": 10,
+		"tags":      []any{"a", nil, 1},
+	}
+
+	tests := []struct {
+		expr string
+		want []any
+	}{
+		{"$.store.book[*].author", []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"$..author", []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{"$.store.*.color", []any{"red"}},
+		{"$..book[2].title", []any{"Moby Dick"}},
+		{"$..book[-1].title", []any{"The Lord of the Rings"}},
+		{"$..book[0,1].title", []any{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[:2].title", []any{"Sayings of the Century", "Sword of Honour"}},
+		{"$..book[::-2].title", []any{"The Lord of the Rings", "Sword of Honour"}},
+		{"$..book[0:4:0]", nil},
+		{"$..book[?@.isbn != ''].title", []any{"Moby Dick", "The Lord of the Rings"}},
+		{"$..book[?@.price < 10].title", []any{"Sayings of the Century", "Moby Dick"}},
+		{"$..book[?@.price > $.expensive].title", []any{"Sword of Honour", "The Lord of the Rings"}},
+		{"$..book[?@.category == 'fiction' && !(@.price > 20)].title", []any{"Sword of Honour", "Moby Dick"}},
+		{"$..book[?@.price < 9 || @.price > 20].title", []any{"Sayings of the Century", "Moby Dick", "The Lord of the Rings"}},
+		{"$..book[?match(@.author, '.*Rees')].title", []any{"Sayings of the Century"}},
+		{"$..book[?search(@.title, 'Dick')].title", []any{"Moby Dick"}},
+		{"$..book[?length(@.title) < 10].title", []any{"Moby Dick"}},
+		{"$.store[?@.color].price", []any{399.0}},
+		{"$[?count(@.*) == 3]", []any{obj["tags"]}},
+		{"$.tags[?@ == null]", []any{nil}},
+		{"$.tags[?@ == 1]", []any{1}},
+		{"$.tags[?value(@) == 'a']", []any{"a"}},
+		{"$.tags['0']", nil},
+		{"$.store.book[9]", nil},
+		{"$.expensive", []any{10}},
+		// RFC 9535: names are case-sensitive, values of different types are not equal, indexes never select members
+		{"$.Expensive", nil},
+		{"$.store.Book[0].Author", []any{"Nigel Rees"}},
+		{"$.tags[?@ == 'a' || @ == '1']", []any{"a"}},
+		{"$.numbers[?@ == 1]", []any{1, 1.0}},
+		{"$.numbers[?@ != 1]", []any{"1", true, nil}},
+		{"$.members[0]", nil},
+		{"$.members['0']", []any{"zero"}},
+		{"$.members[?@ == 'zero']", []any{"zero"}},
+	}
+
+	obj["numbers"] = []any{1, "1", 1.0, true, nil}
+	obj["members"] = map[string]any{"0": "zero"}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		matches, _ := JSONPathResult[any](obj, Tag, tt.expr)
+
+		var got []any
+		for _, match := range matches {
+			got = append(got, match.Value)
+		}
+		assert.Equalf(tt.want, got, "expr: %s", tt.expr)
+	}
+
+	// The convenience function evaluates like RFC 9535 too
+	assert.Equal([]any{1, 1.0}, JSONPath(obj, "$.numbers[?@ == 1]"))
+	assert.Nil(JSONPath(obj, "$.members[0]"))
+	assert.Nil(JSONPath(obj, "$.EXPENSIVE"))
+	assert.Equal([]any{"Nigel Rees"}, JSONPath(obj, "$.store.Book[0].Author"))
+}
+
+func TestJSONPathResult(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+		note string
+	}
+
+	users := []user{{"Alice", 30, "a"}, {"Bob", 25, "b"}}
+
+	assert := assert.New(t)
+
+	matches, err := JSONPathResult[string](users, None, "$[?@.Age >= 25].Name")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{
+		{[]string{"0", "Name"}, "Alice"},
+		{[]string{"1", "Name"}, "Bob"},
+	}, matches)
+
+	// Unexported fields unless safe
+	assert.Equal([]any{"a", "b"}, JSONPath(users, "$[*].note"))
+	matches, err = JSONPathResult[string](users, Safe, "$[*].note")
+	assert.NoError(err)
+	assert.Empty(matches)
+
+	// Case sensitive regardless of options
+	ages, err := JSONPathResult[int](users, None, "$[0].age")
+	assert.NoError(err)
+	assert.Equal([]Match[int]{}, ages)
+
+	// Filters are type strict regardless of options, results are converted unless Type
+	_, err = JSONPathResult[string](users, Type, "$[0].Age")
+	assert.Error(err)
+	matches2, err := JSONPathResult[string](users, None, "$[0].Age")
+	assert.NoError(err)
+	assert.Equal("30", matches2[0].Value)
+	assert.Equal(0, len(JSONPath(users, "$[?@.Age == '30']")))
+	empty, err := JSONPathResult[any](users, None, "$[?@.Age == '30']")
+	assert.NoError(err)
+	assert.Equal([]Match[any]{}, empty)
+
+	// length() of a struct counts the fields which queries can select
+	assert.Equal([]any{users[0]}, JSONPath(users, "$[?length(@) == 3 && @.Age == 30]"))
+	lengths, err := JSONPathResult[user](users, Safe, "$[?length(@) == 2]")
+	assert.NoError(err)
+	assert.Equal(2, len(lengths))
+	type tagged struct {
+		A int
+		B int `json:"-"`
+	}
+	assert.Equal([]any{tagged{1, 2}}, JSONPath([]tagged{{1, 2}}, "$[?length(@) == 2]"))
+	tags, err := JSONPathResult[tagged](map[string]tagged{"t": {1, 2}}, Tag, "$[?length(@) == 1]")
+	assert.NoError(err)
+	assert.Equal([]Match[tagged]{{[]string{"t"}, tagged{1, 2}}}, tags)
+
+	// Syntax error
+	_, err = JSONPathResult[any](users, None, "$[")
+	var queryErr *QueryError
+	if assert.ErrorAs(err, &queryErr) {
+		assert.Equal(ErrSyntax, queryErr.Code)
+	}
+
+	p := MustCompileJSONPath("$[?@.Name == 'Bob'].Age")
+	age, err := p.Int(users, None)
+	assert.NoError(err)
+	assert.Equal(25, age)
+	_, err = MustCompileJSONPath("$[?@.Age == '25'].Name").Str(users, None)
+	assert.Error(err)
+	s, err := MustCompileJSONPath("$[1].Age").Str(users, None)
+	assert.NoError(err)
+	assert.Equal("25", s)
+	_, err = MustCompileJSONPath("$[0]").Any(map[string]any{"0": "zero"}, None)
+	assert.Error(err)
+
+	_, err = CompileJSONPath("name")
+	assert.Error(err)
+	assert.Panics(func() { MustCompileJSONPath("$..") })
+}
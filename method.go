@@ -0,0 +1,117 @@
+package goget
+
+import (
+	"reflect"
+	"strings"
+)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	boolType  = reflect.TypeOf(false)
+)
+
+// walkMethod calls the method named by segment on a Value if option Method is set, and search the result by segments.
+// It returns false ok if there is no such method, so the caller reports its own error.
+func (w *walker) walkMethod(value reflect.Value, seg segment, segs []segment, keys []string) (stop bool, ok bool, err *QueryError) {
+	if w.opt&Method != Method || seg.kind != keySegment {
+		return false, false, nil
+	}
+
+	method, name, ok := findMethod(value, seg.key, w.opt&Case == Case)
+	if !ok {
+		return false, false, nil
+	}
+
+	result, err := callMethod(method, name)
+	if err != nil {
+		return false, true, err
+	}
+
+	stop, err = w.walk(result, segs, w.appendKey(keys, name))
+	if err != nil {
+		return false, true, newQueryError(err, ErrNotFound, "[method] query keys: %s", segmentsToKeys(segs))
+	}
+	return stop, true, nil
+}
+
+// findMethod returns the exported method of a Value by name, on value or pointer receiver.
+// A method with pointer receiver of an unaddressable Value is called on a copy.
+func findMethod(value reflect.Value, name string, caseSensitive bool) (reflect.Value, string, bool) {
+	// Values read from unexported fields cannot be called, copy them via any
+	if value.IsValid() && !value.CanInterface() {
+		value = reflect.ValueOf(valueToAny(value))
+	}
+
+	v := value
+	for v.IsValid() {
+		if method, methodName, ok := methodByName(v, name, caseSensitive); ok {
+			return method, methodName, true
+		}
+
+		if (v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface) || v.IsNil() {
+			break
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		return reflect.Value{}, "", false
+	}
+
+	// Pointer receiver
+	ptr := reflect.New(v.Type())
+	if v.CanAddr() {
+		ptr = v.Addr()
+	} else {
+		ptr.Elem().Set(v)
+	}
+	return methodByName(ptr, name, caseSensitive)
+}
+
+// methodByName returns the method of a Value by name, exactly first, then case-insensitive unless caseSensitive.
+func methodByName(value reflect.Value, name string, caseSensitive bool) (reflect.Value, string, bool) {
+	if method := value.MethodByName(name); method.IsValid() {
+		return method, name, true
+	}
+	if caseSensitive {
+		return reflect.Value{}, "", false
+	}
+
+	for i := 0; i < value.NumMethod(); i++ {
+		methodName := value.Type().Method(i).Name
+		if strings.EqualFold(methodName, strings.TrimSpace(name)) {
+			return value.Method(i), methodName, true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+// callMethod calls a method without arguments, which returns a value, optionally followed by an error or a bool.
+// A non-nil error or false bool means not found.
+func callMethod(method reflect.Value, name string) (reflect.Value, *QueryError) {
+	methodType := method.Type()
+	if methodType.NumIn() != 0 || methodType.IsVariadic() {
+		return reflect.Value{}, newQueryError(nil, ErrNotFound, "[method] %s requires arguments", name)
+	}
+
+	switch methodType.NumOut() {
+	case 1:
+	case 2:
+		if out := methodType.Out(1); out != errorType && out != boolType {
+			return reflect.Value{}, newQueryError(nil, ErrNotFound, "[method] %s second result must be error or bool", name)
+		}
+	default:
+		return reflect.Value{}, newQueryError(nil, ErrNotFound, "[method] %s must return 1 or 2 results", name)
+	}
+
+	results := method.Call(nil)
+	if len(results) == 2 {
+		switch second := results[1]; {
+		case second.Type() == boolType && !second.Bool():
+			return reflect.Value{}, newQueryError(nil, ErrNotFound, "[method] %s returns false", name)
+		case second.Type() == errorType && !second.IsNil():
+			return reflect.Value{}, newQueryError(second.Interface().(error), ErrNotFound, "[method] %s returns error", name)
+		}
+	}
+
+	return results[0], nil
+}
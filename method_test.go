@@ -0,0 +1,139 @@
+package goget
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type methodUser struct {
+	Name  string
+	first string
+	inner *methodUser
+}
+
+func (u methodUser) FullName() string {
+	return u.Name + " " + u.first
+}
+
+func (u *methodUser) GetName() string {
+	if u == nil {
+		return ""
+	}
+	return u.Name
+}
+
+func (u *methodUser) Inner() (*methodUser, error) {
+	if u.inner == nil {
+		return nil, errors.New("no inner")
+	}
+	return u.inner, nil
+}
+
+func (u methodUser) Lookup() (string, bool) {
+	return u.first, u.first != ""
+}
+
+func (u methodUser) Greet(name string) string {
+	return "hi " + name
+}
+
+func (u methodUser) Pair() (string, string) {
+	return u.Name, u.first
+}
+
+func (u methodUser) First() string {
+	return "method"
+}
+
+type methodList []int
+
+func (l methodList) Sum() int {
+	s := 0
+	for _, v := range l {
+		s += v
+	}
+	return s
+}
+
+func TestMethod(t *testing.T) {
+	join := time.Unix(1065430000, 0).In(time.UTC)
+	user := methodUser{Name: "Vin", first: "Mars", inner: &methodUser{Name: "Inner"}}
+	obj := map[string]any{
+		"user":  user,
+		"ptr":   &user,
+		"nil":   (*methodUser)(nil),
+		"join":  join,
+		"list":  methodList{1, 2, 3},
+		"users": []methodUser{user},
+		"empty": methodUser{Name: "Empty"},
+	}
+
+	tests := []struct {
+		paths []string
+		opt   Option
+		want  any
+		err   bool
+	}{
+		{[]string{"user", "FullName"}, Method, "Vin Mars", false},
+		{[]string{"user", "fullname"}, Method, "Vin Mars", false},
+		{[]string{"user", "fullname"}, Method | Case, nil, true},
+		{[]string{"user", "FullName"}, None, nil, true},
+		{[]string{"user", "GetName"}, Method, "Vin", false},
+		{[]string{"ptr", "GetName"}, Method, "Vin", false},
+		{[]string{"ptr", "FullName"}, Method, "Vin Mars", false},
+		{[]string{"users", "0", "GetName"}, Method, "Vin", false},
+		{[]string{"nil", "GetName"}, Method, "", false},
+		{[]string{"user", "Inner", "Name"}, Method, "Inner", false},
+		{[]string{"user", "Inner", "Inner"}, Method | Case, nil, true},
+		{[]string{"user", "Lookup"}, Method, "Mars", false},
+		{[]string{"empty", "Lookup"}, Method, nil, true},
+		{[]string{"user", "Greet"}, Method, nil, true},
+		{[]string{"user", "Pair"}, Method, nil, true},
+		{[]string{"user", "first"}, Method, "Mars", false},
+		{[]string{"user", "First"}, Method, "Mars", false},
+		{[]string{"user", "First"}, Method | Case, "method", false},
+		{[]string{"user", "first"}, Method | Safe, "method", false},
+		{[]string{"user", "inner", "GetName"}, Method, "Inner", false},
+		{[]string{"user", "inner", "Name"}, Safe, nil, true},
+		{[]string{"join", "Unix"}, Method, int64(1065430000), false},
+		{[]string{"join", "Year"}, Method, 2003, false},
+		{[]string{"list", "Sum"}, Method, 6, false},
+		{[]string{"list", "1"}, Method, 2, false},
+		{[]string{"list", "Len"}, Method, nil, true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := AnyResult(obj, tt.opt, tt.paths...)
+		if tt.err {
+			assert.Errorf(err, "paths: %v", tt.paths)
+			continue
+		}
+
+		if !assert.NoErrorf(err, "paths: %v", tt.paths) {
+			continue
+		}
+		assert.Equalf(tt.want, got, "paths: %v", tt.paths)
+	}
+
+	// Errors returned by methods are the cause
+	_, err := AnyResult(obj, Method|Case, "empty", "Inner")
+	assert.ErrorContains(err, "no inner")
+
+	// Fields take precedence over methods, unexported fields are skipped when safe
+	assert.Equal("Inner", MayString(obj, Method|Safe, "user", "inner", "GetName"))
+
+	// Results are converted like other values
+	assert.Equal("2003", MayString(join, M, "Year"))
+
+	matches, err := AllResult[string](obj, M, "users,*,fullname")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{{[]string{"users", "0", "FullName"}, "Vin Mars"}}, matches)
+
+	// Filters match attributes by methods too
+	assert.Equal("Vin", MayString(obj, M, "users,fullname=Vin Mars,name"))
+	_, err = StringResult(obj, None, "users,fullname=Vin Mars,name")
+	assert.Error(err)
+}
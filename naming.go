@@ -0,0 +1,104 @@
+package goget
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// keyMatchers returns functions to match a name with a key, in order of precedence: exactly, then case-insensitive
+// unless option Case, then normalized with option Normalize.
+func keyMatchers(opt Option) []func(name string, key string) bool {
+	matchers := []func(name string, key string) bool{
+		func(name string, key string) bool {
+			return name == key
+		},
+	}
+	if opt&Case != Case {
+		matchers = append(matchers, func(name string, key string) bool {
+			return strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(key))
+		})
+	}
+	if opt&Normalize == Normalize {
+		matchers = append(matchers, func(name string, key string) bool {
+			return strings.EqualFold(normalizeKey(name), normalizeKey(key))
+		})
+	}
+
+	return matchers
+}
+
+// keyForms returns functions to convert a name or a key to the form compared by the matcher of [keyMatchers] in the
+// same position, so that names can be indexed: a name matches a key if and only if their forms are equal.
+func keyForms(opt Option) []func(key string) string {
+	forms := keyFormSets[0]
+	if opt&Case == Case {
+		forms = keyFormSets[1]
+	}
+	if opt&Normalize != Normalize {
+		// The normalized form is the last one
+		forms = forms[:len(forms)-1]
+	}
+
+	return forms
+}
+
+// keyFormSets are the key forms with option Normalize, without and with option Case.
+var keyFormSets = [2][]func(key string) string{
+	{exactKey, foldedKey, normalizedKey},
+	{exactKey, normalizedKey},
+}
+
+// exactKey is the form of exact matches.
+func exactKey(key string) string {
+	return key
+}
+
+// foldedKey is the form of case-insensitive matches.
+func foldedKey(key string) string {
+	return foldKey(strings.TrimSpace(key))
+}
+
+// normalizedKey is the form of normalized matches.
+func normalizedKey(key string) string {
+	return foldKey(normalizeKey(key))
+}
+
+// foldKey replaces each rune of a key by the canonical rune it folds to, see [foldRune], so that keys are equal under
+// strings.EqualFold if and only if their folded keys are equal. Lower-case ASCII keys are returned as is.
+func foldKey(key string) string {
+	return strings.Map(foldRune, key)
+}
+
+// foldRune returns the canonical rune of the Unicode case folding orbit of a rune: the lower-case ASCII letter if the
+// orbit has one, such as k for K and the Kelvin sign, otherwise the least rune.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}
+
+	least := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < utf8.RuneSelf {
+			return foldRune(f)
+		}
+		if f < least {
+			least = f
+		}
+	}
+	return least
+}
+
+// normalizeKey removes separators "_", "-" and spaces from a key, so that snake_case, kebab-case, camelCase and
+// PascalCase names are equal ignoring case.
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, key)
+}
@@ -0,0 +1,125 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"user_name", "username"},
+		{"user-name", "username"},
+		{"userName", "userName"},
+		{" User Name ", "UserName"},
+		{"__a--b__", "ab"},
+		{"", ""},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		assert.Equalf(tt.want, normalizeKey(tt.key), "key: %s", tt.key)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	type account struct {
+		UserName  string
+		UserID    int `json:"user_id"`
+		Nick_Name string
+	}
+	type user struct {
+		Username string
+		UserName string
+	}
+
+	obj := map[string]any{
+		"account": account{"Ann", 7, "annie"},
+		"user":    user{"a", "b"},
+		"payload": map[string]any{"first_name": "Ann", "last-name": "Lee", "zipCode": "12345"},
+		"both":    map[string]int{"user_name": 1, "user-name": 2},
+	}
+
+	tests := []struct {
+		paths []string
+		opt   Option
+		want  any
+		code  ErrCode
+	}{
+		{[]string{"account", "user_name"}, Normalize, "Ann", 0},
+		{[]string{"account", "user-name"}, Normalize, "Ann", 0},
+		{[]string{"account", "userName"}, Normalize, "Ann", 0},
+		{[]string{"account", "user_name"}, None, nil, ErrNotFound},
+		{[]string{"account", "user_name"}, Normalize | Case, "Ann", 0},
+		{[]string{"account", "nickname"}, Normalize, "annie", 0},
+		{[]string{"account", "userId"}, Normalize, 7, 0},
+		{[]string{"account", "userId"}, Normalize | Tag, 7, 0},
+		{[]string{"payload", "FirstName"}, Normalize, "Ann", 0},
+		{[]string{"payload", "lastName"}, Normalize, "Lee", 0},
+		{[]string{"payload", "zip_code"}, Normalize, "12345", 0},
+		{[]string{"payload", "zip_code"}, None, nil, ErrNotFound},
+		// Exact and case-insensitive matches take precedence
+		{[]string{"user", "username"}, Normalize, "a", 0},
+		{[]string{"user", "UserName"}, Normalize, "b", 0},
+		{[]string{"user", "user_name"}, Normalize, "a", 0},
+		{[]string{"user", "user_name"}, Normalize | Unambiguous, nil, ErrAmbiguous},
+		{[]string{"both", "user-name"}, Normalize, 2, 0},
+		{[]string{"both", "userName"}, Normalize, 2, 0},
+		{[]string{"both", "userName"}, Normalize | Unambiguous, nil, ErrAmbiguous},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := AnyResult(obj, tt.opt, tt.paths...)
+		if tt.code != 0 {
+			var queryErr *QueryError
+			if assert.ErrorAsf(err, &queryErr, "paths: %v", tt.paths) {
+				assert.Equalf(tt.code, queryErr.Code, "paths: %v", tt.paths)
+			}
+			continue
+		}
+
+		if !assert.NoErrorf(err, "paths: %v", tt.paths) {
+			continue
+		}
+		assert.Equalf(tt.want, got, "paths: %v", tt.paths)
+	}
+
+	key, err := findMapKey(reflect.ValueOf(obj["payload"]), "First-Name", Normalize)
+	assert.Nil(err)
+	assert.Equal("first_name", key.Interface())
+}
+
+func TestFoldKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"UserName", "username"},
+		{"ſtraße", "STRAßE"},
+		{"K", "k"}, // Kelvin sign
+		{"Σ", "ς"},
+		{"a", "b"},
+		{"ab", "a"},
+		{"", ""},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		assert.Equalf(strings.EqualFold(tt.a, tt.b), foldKey(tt.a) == foldKey(tt.b), "keys: %s, %s", tt.a, tt.b)
+	}
+	assert.Equal("city", foldKey("City"))
+
+	// Every rune of an orbit folds to the same rune
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if foldRune(f) != foldRune(r) {
+				t.Fatalf("runes %q and %q fold to %q and %q", r, f, foldRune(r), foldRune(f))
+			}
+		}
+	}
+}
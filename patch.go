@@ -0,0 +1,337 @@
+package goget
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// patchOption resolves paths of a JSON Patch exactly by the names of fields in JSON, and never touches unexported
+// fields.
+const patchOption = Case | Safe | Tag | tagNamesOnly
+
+// ApplyPatch applies a JSON Patch (RFC 6902) document to an object, such as
+// `[{"op": "replace", "path": "/address/city", "value": "Mesa"}]`. Operations add, remove, replace, move, copy and
+// test are supported.
+//
+// Paths are JSON Pointers resolved with options Case, Safe and Tag, by the names of struct fields in JSON like
+// encoding/json: a field named in a json tag matches only that name exactly, other fields their field names, and
+// unexported fields are never touched. Values are decoded by encoding/json into the type of the target element. Removing a struct field zeroes it, and adding to a slice inserts
+// an element, "-" appends.
+//
+// The patch is applied atomically: operations are applied to a deep copy first, and to the object only if all of them
+// succeed. The object must be a pointer, map or slice. A failed test operation is an ErrTestFailed error.
+func ApplyPatch(obj any, patch []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	ops, parseErr := parsePatch(patch)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	root := reflect.ValueOf(obj)
+	switch root.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+	default:
+		return newQueryError(nil, ErrTypeMatch, "[patch] object must be a pointer, map or slice, got %s", root.Kind())
+	}
+
+	// Dry run on a deep copy, so that a failed operation leaves the object unchanged
+	for _, target := range []reflect.Value{deepCopy(root, make(map[reference]reflect.Value)), root} {
+		for i, op := range ops {
+			if err := op.apply(target); err != nil {
+				err.Detail += fmt.Sprintf(" in operation %d: %s %s", i, op.op, op.rawPath)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// patchOperation is an operation of a JSON Patch.
+type patchOperation struct {
+	op      string
+	rawPath string
+	path    []segment
+	from    []segment
+	value   json.RawMessage
+}
+
+// parsePatch parses a JSON Patch document to operations.
+func parsePatch(patch []byte) ([]patchOperation, *QueryError) {
+	var docs []map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &docs); err != nil {
+		return nil, newQueryError(err, ErrSyntax, "[patch] invalid document")
+	}
+
+	ops := make([]patchOperation, 0, len(docs))
+	for i, doc := range docs {
+		var op patchOperation
+		if err := json.Unmarshal(doc["op"], &op.op); err != nil {
+			return nil, newQueryError(err, ErrSyntax, "[patch] invalid op in operation %d", i)
+		}
+
+		var err *QueryError
+		if op.path, op.rawPath, err = parsePatchPointer(doc, "path"); err != nil {
+			return nil, newQueryError(nil, ErrSyntax, "[patch] %s in operation %d", err.Detail, i)
+		}
+
+		switch op.op {
+		case "add", "replace", "test":
+			var ok bool
+			if op.value, ok = doc["value"]; !ok {
+				return nil, newQueryError(nil, ErrSyntax, "[patch] missing value in operation %d", i)
+			}
+		case "move", "copy":
+			if op.from, _, err = parsePatchPointer(doc, "from"); err != nil {
+				return nil, newQueryError(nil, ErrSyntax, "[patch] %s in operation %d", err.Detail, i)
+			}
+		case "remove":
+		default:
+			return nil, newQueryError(nil, ErrSyntax, "[patch] unknown op %s in operation %d", op.op, i)
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+// parsePatchPointer parses a JSON Pointer member of an operation.
+func parsePatchPointer(doc map[string]json.RawMessage, member string) ([]segment, string, *QueryError) {
+	var pointer string
+	if err := json.Unmarshal(doc[member], &pointer); err != nil {
+		return nil, "", newQueryError(err, ErrSyntax, "invalid %s", member)
+	}
+
+	segs, err := parsePointer(pointer)
+	if err != nil {
+		return nil, "", newQueryError(nil, ErrSyntax, "invalid %s %s", member, pointer)
+	}
+	return segs, pointer, nil
+}
+
+// apply applies an operation to an object.
+func (op patchOperation) apply(root reflect.Value) *QueryError {
+	switch op.op {
+	case "add":
+		return patchAdd(root, op.path, op.value)
+
+	case "remove":
+		return patchRemove(root, op.path)
+
+	case "replace":
+		if _, err := patchGet(root, op.path); err != nil {
+			return err
+		}
+		return patchSet(root, op.path, op.value)
+
+	case "move":
+		if isSegmentsPrefix(op.from, op.path) {
+			if len(op.from) == len(op.path) {
+				return nil
+			}
+			return newQueryError(nil, ErrSyntax, "[patch] cannot move a value into its child")
+		}
+		value, err := patchGet(root, op.from)
+		if err != nil {
+			return err
+		}
+		if err := patchRemove(root, op.from); err != nil {
+			return err
+		}
+		return patchAdd(root, op.path, value)
+
+	case "copy":
+		value, err := patchGet(root, op.from)
+		if err != nil {
+			return err
+		}
+		return patchAdd(root, op.path, value)
+
+	case "test":
+		value, err := patchGet(root, op.path)
+		if err != nil {
+			return err
+		}
+
+		var got, want any
+		if err := json.Unmarshal(value, &got); err != nil {
+			return newQueryError(err, ErrTypeMatch, "[patch] cannot compare value")
+		}
+		if err := json.Unmarshal(op.value, &want); err != nil {
+			return newQueryError(err, ErrSyntax, "[patch] invalid value")
+		}
+		if !reflect.DeepEqual(got, want) {
+			return newQueryError(nil, ErrTestFailed, "[patch] test failed: got %s", value)
+		}
+		return nil
+	}
+
+	return newQueryError(nil, ErrSyntax, "[patch] unknown op %s", op.op)
+}
+
+// patchGet returns the element of an object by segments in JSON.
+func patchGet(root reflect.Value, segs []segment) (json.RawMessage, *QueryError) {
+	result := querySegments(root.Interface(), patchOption, defaultTagNames, segs)
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	value, err := json.Marshal(valueToAny(result.val))
+	if err != nil {
+		return nil, newQueryError(err, ErrTypeMatch, "[patch] cannot encode value")
+	}
+	return value, nil
+}
+
+// patchAdd inserts a value into a slice at the index of the last segment, "-" appends, or sets the value otherwise.
+func patchAdd(root reflect.Value, segs []segment, value json.RawMessage) *QueryError {
+	if len(segs) == 0 {
+		return patchSet(root, segs, value)
+	}
+
+	parentSegs, last := segs[:len(segs)-1], segs[len(segs)-1]
+	parent := querySegments(root.Interface(), patchOption, defaultTagNames, parentSegs)
+	if parent.err != nil {
+		return parent.err
+	}
+	concrete, err := toConcreteElem(parent.val, true, 0)
+	switch {
+	case err == nil && concrete.Kind() == reflect.Array:
+		return newQueryError(nil, ErrTypeMatch, "[patch] cannot add to array %s", concrete.Type())
+	case err != nil || concrete.Kind() != reflect.Slice:
+		return patchSet(root, segs, value)
+	}
+
+	return updateSlice(root, patchOption, parentSegs, func(slice reflect.Value) (reflect.Value, *QueryError) {
+		index := slice.Len()
+		if last.key != "-" {
+			if !last.isIndex || last.index > slice.Len() {
+				return slice, newQueryError(nil, ErrNotFound, "[patch] invalid index %s", last.key)
+			}
+			index = last.index
+		}
+
+		elem, err := decodeJSON(value, slice.Type().Elem())
+		if err != nil {
+			return slice, err
+		}
+		return insertElem(slice, index, elem), nil
+	})
+}
+
+// patchSet sets a value to the element of an object by segments, a missing map key is added.
+func patchSet(root reflect.Value, segs []segment, value json.RawMessage) *QueryError {
+	u := &updater{opt: patchOption, update: func(old reflect.Value) (reflect.Value, *QueryError) {
+		return decodeJSON(value, old.Type())
+	}}
+	return u.updateRoot(root, segs)
+}
+
+// patchRemove removes the element of an object by segments.
+func patchRemove(root reflect.Value, segs []segment) *QueryError {
+	if len(segs) == 0 {
+		return newQueryError(nil, ErrNotFound, "[patch] cannot remove the object")
+	}
+
+	u := &updater{opt: patchOption, remove: true}
+	return u.updateRoot(root, segs)
+}
+
+// decodeJSON decodes a JSON value into a new Value of a type.
+func decodeJSON(data json.RawMessage, typ reflect.Type) (reflect.Value, *QueryError) {
+	value := reflect.New(typ)
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return value.Elem(), newQueryError(err, ErrTypeMatch, "[patch] cannot decode %s into %s", data, typ)
+	}
+	return value.Elem(), nil
+}
+
+// isSegmentsPrefix reports whether segments prefix are a prefix of segments segs.
+func isSegmentsPrefix(prefix []segment, segs []segment) bool {
+	if len(prefix) > len(segs) {
+		return false
+	}
+	for i, seg := range prefix {
+		if seg.key != segs[i].key {
+			return false
+		}
+	}
+	return true
+}
+
+// deepCopy returns a deep copy of a Value, including unexported fields. Shared and cyclic pointers and maps are
+// copied once.
+func deepCopy(value reflect.Value, copies map[reference]reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Invalid:
+		// Nil interfaces of unexported fields
+		return value
+
+	case reflect.Pointer, reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		ref := reference{value.Pointer(), value.Type()}
+		if copied, ok := copies[ref]; ok {
+			return copied
+		}
+
+		if value.Kind() == reflect.Pointer {
+			copied := reflect.New(value.Type().Elem())
+			copies[ref] = copied
+			setOrZero(copied.Elem(), deepCopy(writable(value.Elem()), copies))
+			return copied
+		}
+
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		copies[ref] = copied
+		for _, key := range value.MapKeys() {
+			elem := reflect.New(value.Type().Elem()).Elem()
+			setOrZero(elem, deepCopy(writable(value.MapIndex(key)), copies))
+			copied.SetMapIndex(writable(key), elem)
+		}
+		return copied
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		setOrZero(copied, deepCopy(writable(value.Elem()), copies))
+		return copied
+
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			setOrZero(copied.Index(i), deepCopy(writable(value.Index(i)), copies))
+		}
+		return copied
+
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			setOrZero(copied.Index(i), deepCopy(writable(value.Index(i)), copies))
+		}
+		return copied
+
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			setOrZero(writable(copied.Field(i)), deepCopy(writable(value.Field(i)), copies))
+		}
+		return copied
+	}
+
+	return value
+}
@@ -0,0 +1,219 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestParsePatch(t *testing.T) {
+	tests := []struct {
+		patch string
+		ops   []string
+		err   bool
+	}{
+		{`[]`, []string{}, false},
+		{`[{"op": "add", "path": "/a", "value": 1}, {"op": "remove", "path": "/a"}]`, []string{"add", "remove"}, false},
+		{`[{"op": "add", "path": "/a", "value": null}]`, []string{"add"}, false},
+		{`[{"op": "move", "from": "/a", "path": "/b"}, {"op": "copy", "from": "", "path": "/b"}]`, []string{"move", "copy"}, false},
+		{`[{"op": "test", "path": "/a~1b", "value": "x"}]`, []string{"test"}, false},
+		{`{"op": "add"}`, nil, true},
+		{`[{"path": "/a"}]`, nil, true},
+		{`[{"op": "merge", "path": "/a"}]`, nil, true},
+		{`[{"op": "add", "path": "/a"}]`, nil, true},
+		{`[{"op": "remove"}]`, nil, true},
+		{`[{"op": "remove", "path": "a"}]`, nil, true},
+		{`[{"op": "remove", "path": "/a~2"}]`, nil, true},
+		{`[{"op": "move", "path": "/a"}]`, nil, true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		ops, err := parsePatch([]byte(tt.patch))
+		if tt.err {
+			if assert.NotNilf(err, "patch: %s", tt.patch) {
+				assert.Equalf(ErrSyntax, err.Code, "patch: %s", tt.patch)
+			}
+			continue
+		}
+
+		if !assert.Nilf(err, "patch: %s", tt.patch) {
+			continue
+		}
+		names := make([]string, len(ops))
+		for i, op := range ops {
+			names[i] = op.op
+		}
+		assert.Equalf(tt.ops, names, "patch: %s", tt.patch)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	type address struct {
+		City    string `json:"city"`
+		ZipCode string `json:"zip_code"`
+	}
+	type user struct {
+		ID       int               `json:"id"`
+		Name     string            `json:"name"`
+		Secret   string            `json:"-"`
+		Address  *address          `json:"address,omitempty"`
+		Tags     []string          `json:"tags"`
+		Scores   [2]int            `json:"scores"`
+		Meta     map[string]any    `json:"meta"`
+		Contacts []address         `json:"contacts"`
+		Labels   map[string]string `json:"labels"`
+		Plain    string
+		Note     string `json:",omitempty"`
+		password string
+	}
+
+	newUser := func() *user {
+		return &user{
+			ID:       1,
+			Name:     "Ann",
+			Secret:   "s",
+			Address:  &address{"Mesa", "85201"},
+			Tags:     []string{"a", "b"},
+			Meta:     map[string]any{"level": 1.0, "nested": map[string]any{"x": "y"}},
+			Contacts: []address{{City: "Rome"}},
+			Labels:   map[string]string{"env": "prod"},
+			Plain:    "p",
+			Note:     "n",
+			password: "pw",
+		}
+	}
+
+	tests := []struct {
+		patch string
+		get   []string
+		want  any
+		code  ErrCode
+	}{
+		// add
+		{`[{"op": "add", "path": "/name", "value": "Bob"}]`, []string{"name"}, "Bob", 0},
+		{`[{"op": "add", "path": "/address/zip_code", "value": "85281"}]`, []string{"address", "zipcode"}, "85281", 0},
+		{`[{"op": "add", "path": "/tags/1", "value": "x"}]`, []string{"tags"}, []string{"a", "x", "b"}, 0},
+		{`[{"op": "add", "path": "/tags/-", "value": "x"}]`, []string{"tags"}, []string{"a", "b", "x"}, 0},
+		{`[{"op": "add", "path": "/tags/3", "value": "x"}]`, nil, nil, ErrNotFound},
+		{`[{"op": "add", "path": "/tags/01", "value": "x"}]`, nil, nil, ErrNotFound},
+		{`[{"op": "add", "path": "/tags/0", "value": 1}]`, nil, nil, ErrTypeMatch},
+		{`[{"op": "add", "path": "/scores/0", "value": 1}]`, nil, nil, ErrTypeMatch},
+		{`[{"op": "add", "path": "/meta/new", "value": {"a": [1, 2]}}]`, []string{"meta", "new", "a", "1"}, 2.0, 0},
+		{`[{"op": "add", "path": "/labels/team", "value": "core"}]`, []string{"labels", "team"}, "core", 0},
+		{`[{"op": "add", "path": "/contacts/0", "value": {"city": "Oslo", "zip_code": "0150"}}]`, []string{"contacts", "0"}, address{"Oslo", "0150"}, 0},
+		{`[{"op": "add", "path": "/missing", "value": 1}]`, nil, nil, ErrNotFound},
+		{`[{"op": "add", "path": "/missing/a", "value": 1}]`, nil, nil, ErrNotFound},
+		// remove
+		{`[{"op": "remove", "path": "/tags/0"}]`, []string{"tags"}, []string{"b"}, 0},
+		{`[{"op": "remove", "path": "/meta/level"}]`, []string{"meta", "keys()"}, []string{"nested"}, 0},
+		{`[{"op": "remove", "path": "/address"}]`, []string{"address"}, (*address)(nil), 0},
+		{`[{"op": "remove", "path": "/meta/missing"}]`, nil, nil, ErrNotFound},
+		{`[{"op": "remove", "path": "/tags/-"}]`, nil, nil, ErrNotFound},
+		// replace
+		{`[{"op": "replace", "path": "/id", "value": 2}]`, []string{"id"}, 2, 0},
+		{`[{"op": "replace", "path": "/address", "value": {"city": "Lima"}}]`, []string{"address"}, &address{City: "Lima"}, 0},
+		{`[{"op": "replace", "path": "/scores/1", "value": 5}]`, []string{"scores"}, [2]int{0, 5}, 0},
+		{`[{"op": "replace", "path": "/meta/missing", "value": 1}]`, nil, nil, ErrNotFound},
+		{`[{"op": "replace", "path": "/id", "value": "2"}]`, nil, nil, ErrTypeMatch},
+		// move and copy
+		{`[{"op": "move", "from": "/tags/0", "path": "/tags/-"}]`, []string{"tags"}, []string{"b", "a"}, 0},
+		{`[{"op": "move", "from": "/address/city", "path": "/name"}]`, []string{"address", "city"}, "", 0},
+		{`[{"op": "move", "from": "/meta/nested", "path": "/meta/moved"}]`, []string{"meta", "moved", "x"}, "y", 0},
+		{`[{"op": "move", "from": "/meta", "path": "/meta/nested"}]`, nil, nil, ErrSyntax},
+		{`[{"op": "move", "from": "/tags", "path": "/tags"}]`, []string{"tags"}, []string{"a", "b"}, 0},
+		{`[{"op": "copy", "from": "/address", "path": "/contacts/-"}]`, []string{"contacts", "1"}, address{"Mesa", "85201"}, 0},
+		{`[{"op": "copy", "from": "/address", "path": "/meta/addr"}]`, []string{"meta", "addr"}, map[string]any{"city": "Mesa", "zip_code": "85201"}, 0},
+		{`[{"op": "copy", "from": "/missing", "path": "/name"}]`, nil, nil, ErrNotFound},
+		// test
+		{`[{"op": "test", "path": "/address", "value": {"zip_code": "85201", "city": "Mesa"}}]`, []string{"name"}, "Ann", 0},
+		{`[{"op": "test", "path": "/meta/level", "value": 1}]`, []string{"name"}, "Ann", 0},
+		{`[{"op": "test", "path": "/name", "value": "Bob"}]`, nil, nil, ErrTestFailed},
+		{`[{"op": "test", "path": "/Plain", "value": "p"}]`, []string{"name"}, "Ann", 0},
+		// names are exact names in JSON, unexported and ignored fields are never touched
+		{`[{"op": "replace", "path": "/Name", "value": "Bob"}]`, nil, nil, ErrNotFound},
+		{`[{"op": "replace", "path": "/Address/city", "value": "Lima"}]`, nil, nil, ErrNotFound},
+		{`[{"op": "replace", "path": "/address/City", "value": "Lima"}]`, nil, nil, ErrNotFound},
+		{`[{"op": "replace", "path": "/Note", "value": "x"}]`, []string{"note"}, "x", 0},
+		{`[{"op": "replace", "path": "/plain", "value": "Bob"}]`, nil, nil, ErrNotFound},
+		{`[{"op": "replace", "path": "/Secret", "value": "x"}]`, nil, nil, ErrNotFound},
+		{`[{"op": "replace", "path": "/password", "value": "x"}]`, nil, nil, ErrNotFound},
+		// whole object
+		{`[{"op": "replace", "path": "", "value": {"name": "Eve"}}]`, []string{"name"}, "Eve", 0},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		u := newUser()
+		err := ApplyPatch(u, []byte(tt.patch))
+		if tt.code != 0 {
+			var queryErr *QueryError
+			if assert.ErrorAsf(err, &queryErr, "patch: %s", tt.patch) {
+				assert.Equalf(tt.code, queryErr.Code, "patch: %s", tt.patch)
+			}
+			assert.Equalf(newUser(), u, "patch: %s", tt.patch)
+			continue
+		}
+
+		if !assert.NoErrorf(err, "patch: %s", tt.patch) {
+			continue
+		}
+		assert.Equalf(tt.want, Any(u, tt.get...), "patch: %s", tt.patch)
+	}
+
+	// Operations are applied in order, and atomically
+	u := newUser()
+	home := u.Address
+	err := ApplyPatch(u, []byte(`[
+		{"op": "replace", "path": "/address/city", "value": "Tempe"},
+		{"op": "test", "path": "/address/city", "value": "Tempe"},
+		{"op": "add", "path": "/tags/-", "value": "c"}
+	]`))
+	assert.NoError(err)
+	assert.Equal("Tempe", home.City)
+	assert.Equal([]string{"a", "b", "c"}, u.Tags)
+
+	u = newUser()
+	err = ApplyPatch(u, []byte(`[
+		{"op": "replace", "path": "/address/city", "value": "Tempe"},
+		{"op": "remove", "path": "/tags/0"},
+		{"op": "test", "path": "/address/city", "value": "Mesa"}
+	]`))
+	assert.ErrorContains(err, "in operation 2: test /address/city")
+	assert.Equal(newUser(), u)
+
+	// Maps are patched in place, other objects must be pointers
+	m := map[string]any{"a": []any{1.0}}
+	assert.NoError(ApplyPatch(m, []byte(`[{"op": "add", "path": "/a/0", "value": 0}, {"op": "add", "path": "/b", "value": true}]`)))
+	assert.Equal(map[string]any{"a": []any{0.0, 1.0}, "b": true}, m)
+	assert.Error(ApplyPatch(*newUser(), []byte(`[{"op": "replace", "path": "/id", "value": 2}]`)))
+	assert.Error(ApplyPatch(u, []byte(`not json`)))
+}
+
+func TestDeepCopy(t *testing.T) {
+	type node struct {
+		Name     string
+		Next     *node
+		Children []*node
+		Meta     map[string]any
+		value    any
+		empty    any
+	}
+
+	leaf := &node{Name: "leaf"}
+	root := &node{Name: "root", Children: []*node{leaf, leaf}, Meta: map[string]any{"a": []int{1}}, value: [2]int{1, 2}}
+	root.Next = root
+
+	copied := deepCopy(reflect.ValueOf(root), make(map[reference]reflect.Value)).Interface().(*node)
+	assert := assert.New(t)
+	assert.Equal("root", copied.Name)
+	assert.NotSame(root, copied)
+	assert.Same(copied, copied.Next)
+	assert.NotSame(leaf, copied.Children[0])
+	assert.Same(copied.Children[0], copied.Children[1])
+	assert.Equal([2]int{1, 2}, copied.value)
+	assert.Nil(copied.empty)
+
+	copied.Meta["a"].([]int)[0] = 2
+	assert.Equal([]int{1}, root.Meta["a"])
+}
@@ -0,0 +1,519 @@
+package goget
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled path. It parses paths once and can be used to query many objects.
+// A Path is safe for concurrent use.
+type Path struct {
+	segs     []segment
+	opt      Option   // options always applied when searching, such as Case and Type of JSONPath
+	tagNames []string // struct tags to match keys with option Tag
+}
+
+// segmentKind is the kind of a segment.
+type segmentKind uint8
+
+const (
+	keySegment      segmentKind = iota // map key, struct field or slice index
+	filterSegment                      // slice or map filter: k=v, k>v, ..., ?k=v selects every matched element
+	wildcardSegment                    // every child: *
+	descentSegment                     // the node and all its descendants: ..
+	rangeSegment                       // slice range: start:end:step
+	unionSegment                       // any of alternative segments: [a,b]
+	functionSegment                    // function on the node: len(), keys(), values(), type()
+)
+
+// segment is a parsed key of a path.
+type segment struct {
+	kind    segmentKind
+	key     string // raw key, used as map key or struct field name
+	index   int    // slice index, negative counts from the end
+	isIndex bool   // whether key is a valid slice index (including first and last)
+	strict  bool   // index selects only slice elements, never a map key or struct field
+	literal bool   // special key of paths, which yields to an existing map key or struct field of the same key
+	filter  filter // slice, map or struct filter
+	every   bool   // filter selects every matched element, otherwise the first one; range selects elements, otherwise the sub-slice
+	rng     *sliceRange
+	union   []segment   // alternatives of union
+	err     *QueryError // syntax error of a filter, raised by Compile, or by getters only if the key is not an existing map key or struct field
+}
+
+// sliceRange selects slice elements like Python slicing, negative bounds count from the end.
+type sliceRange struct {
+	start, end       int
+	hasStart, hasEnd bool
+	step             int
+}
+
+// scanner is the input and position of a path parser.
+type scanner struct {
+	notation string // name of the path notation in errors, such as jsonpath
+	s        string
+	pos      int
+}
+
+// errorf returns a syntax error at the current position.
+func (p *scanner) errorf(format string, args ...any) *QueryError {
+	err := newQueryError(nil, ErrSyntax, format, args...)
+	err.Detail += " at " + strconv.Itoa(p.pos) + " of " + p.notation + ": " + p.s
+	return err
+}
+
+// Compile parses paths into a [Path] and reports syntax errors.
+// Paths are in the same format as the package-level getters, but a key which is not a valid filter, such as `a=~*`, is
+// a syntax error rather than a literal key. Escape such a key with a leading backslash.
+func Compile(paths ...string) (*Path, error) {
+	segs, err := parsePaths(paths)
+	if err != nil {
+		return nil, err
+	}
+	if err := filterError(segs); err != nil {
+		return nil, err
+	}
+
+	return &Path{segs: segs, tagNames: defaultTagNames}, nil
+}
+
+// filterError returns the syntax error of the first key which is not a valid filter, or nil.
+func filterError(segs []segment) *QueryError {
+	for _, seg := range segs {
+		if seg.err != nil {
+			return seg.err
+		}
+	}
+	return nil
+}
+
+// MustCompile like [Compile], but panics on error.
+func MustCompile(paths ...string) *Path {
+	p, err := Compile(paths...)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// Any like [AnyResult], but queries by the compiled path.
+func (p *Path) Any(obj any, opt Option) (_ any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToAny[any](p.result(obj, opt), opt&Type == Type)
+}
+
+// Str like [StringResult], but queries by the compiled path. It is a getter, not named String so that it is never
+// mistaken for [fmt.Stringer].
+func (p *Path) Str(obj any, opt Option) (_ string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToAny[string](p.result(obj, opt), opt&Type == Type)
+}
+
+// Int like [IntResult], but queries by the compiled path.
+func (p *Path) Int(obj any, opt Option) (_ int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToAny[int](p.result(obj, opt), opt&Type == Type)
+}
+
+// Uint like [UintResult], but queries by the compiled path.
+func (p *Path) Uint(obj any, opt Option) (_ uint, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToAny[uint](p.result(obj, opt), opt&Type == Type)
+}
+
+// Complex like [ComplexResult], but queries by the compiled path.
+func (p *Path) Complex(obj any, opt Option) (_ complex128, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToAny[complex128](p.result(obj, opt), opt&Type == Type)
+}
+
+// Float like [FloatResult], but queries by the compiled path.
+func (p *Path) Float(obj any, opt Option) (_ float64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToAny[float64](p.result(obj, opt), opt&Type == Type)
+}
+
+// Bool like [BoolResult], but queries by the compiled path.
+func (p *Path) Bool(obj any, opt Option) (_ bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToAny[bool](p.result(obj, opt), opt&Type == Type)
+}
+
+// PathSlice like [SliceResult], but queries by the compiled path.
+func PathSlice[E any](p *Path, obj any, opt Option) (_ []E, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToSlice[E](p.result(obj, opt), opt&Type == Type)
+}
+
+// PathMap like [MapResult], but queries by the compiled path.
+func PathMap[K comparable, E any](p *Path, obj any, opt Option) (_ map[K]E, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	return resultToMap[K, E](p.result(obj, opt), opt&Type == Type)
+}
+
+// PathAll like [AllResult], but queries by the compiled path.
+func PathAll[E any](p *Path, obj any, opt Option) (_ []Match[E], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	results, queryErr := queryAll(obj, opt|p.opt, p.tagNames, p.segs)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+
+	return resultsToMatches[E](results, opt&Type == Type)
+}
+
+// result search an object's elements by the compiled path and returns the result element.
+func (p *Path) result(obj any, opt Option) Result {
+	return querySegments(obj, opt|p.opt, p.tagNames, p.segs)
+}
+
+// WithTagNames returns a copy of the Path, which matches keys with option Tag by names in the struct tags, in order of
+// precedence, before the field names. The default tag is json, no tags match only field names.
+func (p *Path) WithTagNames(tagNames ...string) *Path {
+	copied := *p
+	copied.tagNames = append([]string{}, tagNames...)
+	return &copied
+}
+
+// parsePaths parses paths to segments.
+func parsePaths(paths []string) ([]segment, *QueryError) {
+	return parseKeys(pathsToKeys(paths))
+}
+
+// parseKeys parses normalized keys to segments.
+func parseKeys(keys []string) ([]segment, *QueryError) {
+	segs := make([]segment, 0, len(keys))
+	for _, key := range keys {
+		seg, err := parseKey(key)
+		if err != nil {
+			return nil, err
+		}
+		segs = append(segs, seg)
+	}
+
+	return segs, nil
+}
+
+// parseKey parses a single key to segment.
+// A wildcard, descent, function or filter is a special key, unless it is an existing map key or struct field when
+// applied. A special key escaped by a leading backslash, such as `\*`, is always a literal map key or field name, see
+// [isEscapedKey].
+func parseKey(key string) (segment, *QueryError) {
+	if seg, ok := parsePlainKey(key); ok {
+		return seg, nil
+	}
+	if isEscapedKey(key) {
+		return segment{key: key[1:]}, nil
+	}
+
+	seg := segment{key: key, literal: true}
+
+	switch key {
+	case "*":
+		seg.kind = wildcardSegment
+		return seg, nil
+	case "..":
+		seg.kind = descentSegment
+		return seg, nil
+	}
+
+	if _, ok := pathFunctions[key]; ok {
+		seg.kind = functionSegment
+		return seg, nil
+	}
+
+	if isFilter(key) {
+		// "?filter" selects every matched element
+		every := strings.HasPrefix(key, "?")
+
+		// A key which fails to parse may still be a literal key, the error is raised when it is applied as a filter
+		f, err := parseFilter(strings.TrimPrefix(key, "?"))
+		seg.kind = filterSegment
+		seg.filter = f
+		seg.every = every
+		seg.err = err
+		return seg, nil
+	}
+
+	if strings.Contains(key, ":") {
+		if rng, ok := parseRange(key); ok {
+			seg.kind = rangeSegment
+			seg.rng = rng
+			return seg, nil
+		}
+	}
+
+	return keySegmentOf(key), nil
+}
+
+// specialKeyChars are the characters of special keys: escapes, wildcards, descents, filters, ranges and functions.
+var specialKeyChars = func() (chars [256]bool) {
+	for _, c := range []byte(`\*.=<>:(`) {
+		chars[c] = true
+	}
+	return chars
+}()
+
+// isPlainKey reports whether a key has no character of special keys, cheaply on hot paths.
+func isPlainKey(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if specialKeyChars[key[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePlainKey parses a plain key, see [isPlainKey], to a key segment. It returns false ok for other keys.
+func parsePlainKey(key string) (segment, bool) {
+	if !isPlainKey(key) {
+		return segment{}, false
+	}
+
+	return keySegmentOf(key), true
+}
+
+// keySegmentOf returns the key segment of a map key, struct field or slice index, including first and last.
+func keySegmentOf(key string) segment {
+	seg := segment{key: key}
+
+	switch {
+	case len(key) == 5 && strings.EqualFold(key, "first"):
+		seg.index, seg.isIndex = 0, true
+	case len(key) == 4 && strings.EqualFold(key, "last"):
+		seg.index, seg.isIndex = -1, true
+	case isIndexKey(key):
+		if index, err := strconv.Atoi(key); err == nil {
+			seg.index, seg.isIndex = index, true
+		}
+	}
+
+	return seg
+}
+
+// isEscapedKey reports whether a key is a backslash followed by a special key: a wildcard, descent, function, filter,
+// range, or another escaped key. A backslash before other keys, such as `\n`, is a part of the literal key.
+func isEscapedKey(key string) bool {
+	if !strings.HasPrefix(key, `\`) {
+		return false
+	}
+
+	rest := key[1:]
+	if _, ok := pathFunctions[rest]; ok {
+		return true
+	}
+	if _, ok := parseRange(rest); ok && strings.Contains(rest, ":") {
+		return true
+	}
+	return rest == "*" || rest == ".." || isFilter(rest) || strings.HasPrefix(rest, `\`)
+}
+
+// isIndexKey reports whether a key looks like a slice index: digits with an optional sign, so that other keys are
+// never converted by strconv.Atoi.
+func isIndexKey(key string) bool {
+	if key != "" && (key[0] == '+' || key[0] == '-') {
+		key = key[1:]
+	}
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < '0' || key[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseRange parses a key in format start:end:step to slice range.
+// It returns false ok if key is not a range, so it can be used as a map key. A step 0 selects nothing, so such a key,
+// such as a time "10:00:00", is never a range.
+func parseRange(key string) (_ *sliceRange, ok bool) {
+	parts := strings.Split(key, ":")
+	if len(parts) > 3 {
+		return nil, false
+	}
+
+	bounds := make([]int, 3)
+	has := make([]bool, 3)
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bound, convErr := strconv.Atoi(part)
+		if convErr != nil {
+			return nil, false
+		}
+		bounds[i], has[i] = bound, true
+	}
+
+	rng := &sliceRange{
+		start:    bounds[0],
+		hasStart: has[0],
+		end:      bounds[1],
+		hasEnd:   has[1],
+		step:     1,
+	}
+	if has[2] {
+		if bounds[2] == 0 {
+			return nil, false
+		}
+		rng.step = bounds[2]
+	}
+
+	return rng, true
+}
+
+// indices returns indexes of a slice with length selected by the range.
+func (r *sliceRange) indices(length int) []int {
+	// Normalize bound: negative counts from the end, then clamp to [lower, upper]
+	normalize := func(bound int, lower, upper int) int {
+		if bound < 0 {
+			bound += length
+		}
+		if bound < lower {
+			return lower
+		}
+		if bound > upper {
+			return upper
+		}
+		return bound
+	}
+
+	indices := make([]int, 0)
+	if r.step == 0 {
+		return indices
+	}
+	if r.step > 0 {
+		start, end := 0, length
+		if r.hasStart {
+			start = normalize(r.start, 0, length)
+		}
+		if r.hasEnd {
+			end = normalize(r.end, 0, length)
+		}
+		for i := start; i < end; i += r.step {
+			indices = append(indices, i)
+		}
+	} else {
+		start, end := length-1, -1
+		if r.hasStart {
+			start = normalize(r.start, -1, length-1)
+		}
+		if r.hasEnd {
+			end = normalize(r.end, -1, length-1)
+		}
+		for i := start; i > end; i += r.step {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices
+}
+
+// querySegments search an object's elements by segments and returns the result element.
+func querySegments(obj any, opt Option, tagNames []string, segs []segment) Result {
+	if len(segs) == 0 {
+		return Result{val: reflect.ValueOf(obj)}
+	}
+
+	value, err := query(reflect.ValueOf(obj), opt, tagNames, segs)
+	if err != nil {
+		return Result{err: err}
+	}
+
+	return Result{val: value}
+}
+
+// segmentsToKeys returns raw keys of segments.
+func segmentsToKeys(segs []segment) []string {
+	keys := make([]string, len(segs))
+	for i, seg := range segs {
+		keys[i] = seg.key
+	}
+
+	return keys
+}
+
+// queryAll search an object's elements by segments and returns all result elements with their keys.
+func queryAll(obj any, opt Option, tagNames []string, segs []segment) ([]Match[reflect.Value], *QueryError) {
+	if len(segs) == 0 {
+		return []Match[reflect.Value]{{Keys: []string{}, Value: reflect.ValueOf(obj)}}, nil
+	}
+
+	matches := make([]Match[reflect.Value], 0)
+	w := &walker{opt: opt, tagNames: tagNames, root: reflect.ValueOf(obj), withKeys: true, visit: func(v reflect.Value, keys []string) bool {
+		matches = append(matches, Match[reflect.Value]{Keys: keys, Value: v})
+		return true
+	}}
+
+	if _, err := w.walk(reflect.ValueOf(obj), segs, []string{}); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,300 @@
+package goget
+
+import (
+	"fmt"
+	"github.com/richardliao/goget/internal/ggtest"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		index   int
+		isIndex bool
+		filter  bool
+	}{
+		{"", 0, false, false},
+		{"a", 0, false, false},
+		{"1", 1, true, false},
+		{"-1", -1, true, false},
+		{"first", 0, true, false},
+		{"Last", -1, true, false},
+		{"a=b", 0, false, true},
+		{"=b", 0, false, true},
+		{"a=", 0, false, true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := parseKey(tt.key)
+		assert.Nilf(err, "key: %s", tt.key)
+		assert.Equalf(tt.key, got.key, "key: %s", tt.key)
+		assert.Equalf(tt.index, got.index, "key: %s", tt.key)
+		assert.Equalf(tt.isIndex, got.isIndex, "key: %s", tt.key)
+		assert.Equalf(tt.filter, got.filter != nil, "key: %s", tt.key)
+	}
+}
+
+func TestIsEscapedKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+		raw  string
+	}{
+		{`\*`, true, "*"},
+		{`\..`, true, ".."},
+		{`\len()`, true, "len()"},
+		{`\a=b`, true, "a=b"},
+		{`\?a>1`, true, "?a>1"},
+		{`\1:3`, true, "1:3"},
+		{`\\`, true, `\`},
+		{`\\n`, true, `\n`},
+		{`\n`, false, `\n`},
+		{`\1`, false, `\1`},
+		{`\first`, false, `\first`},
+		{`\`, false, `\`},
+		{"*", false, "*"},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		assert.Equalf(tt.want, isEscapedKey(tt.key), "key: %s", tt.key)
+		seg, err := parseKey(tt.key)
+		if assert.Nilf(err, "key: %s", tt.key) {
+			assert.Equalf(tt.raw, seg.key, "key: %s", tt.key)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	i := 1<<16 + 1
+	s := strconv.Itoa(i)
+	privMap := map[string]any{"A": i}
+	privStruct := ggtest.NewTestPrivStruct(i)
+	pubStruct := ggtest.NewTestPubStruct(i, "PubAny", "PubString", privStruct, "privAny", "privString", privStruct, privMap)
+
+	tests := []struct {
+		value  any
+		opt    Option
+		paths  []string
+		expect any
+		err    bool
+	}{
+		{nil, None, nil, nil, false},
+		{"string", None, nil, "string", false},
+		{pubStruct, None, []string{"PubAny"}, pubStruct.PubAny, false},
+		{pubStruct, None, []string{"PubAny2"}, nil, true},
+		{pubStruct, None, []string{"privStruct2, privString1"}, &s, false},
+		{pubStruct, Safe, []string{"privStruct", "privString"}, nil, true},
+		{pubStruct, None, []string{"privStruct", "privSlice2", "last", strconv.Itoa(i + 1)}, i + 1, false},
+		{pubStruct, None, []string{"privStructs", fmt.Sprintf("privint=%d", i), "privSlice2", fmt.Sprintf("%d=%d", i, i), strconv.Itoa(i)}, i, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		p, err := Compile(tt.paths...)
+		if !assert.NoErrorf(err, "paths: %v", tt.paths) {
+			continue
+		}
+
+		got, err := p.Any(tt.value, tt.opt)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("expect error got nil")
+			}
+			continue
+		}
+
+		if !assert.Equalf(tt.expect, got, "got: %+v", got) {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		// Compiled path must be reusable and agree with package-level getters.
+		again, _ := p.Any(tt.value, tt.opt)
+		assert.Equalf(got, again, "got: %+v", again)
+		expect, _ := AnyResult(tt.value, tt.opt, tt.paths...)
+		assert.Equalf(expect, got, "got: %+v", got)
+	}
+}
+
+func TestPathTyped(t *testing.T) {
+	m := map[string]any{
+		"a": map[string]any{"b": []any{"1", 2, 3.5, true}},
+		"m": map[string]any{"k": "v"},
+	}
+
+	assert := assert.New(t)
+
+	got, err := MustCompile("a,b,0").Int(m, None)
+	assert.NoError(err)
+	assert.Equal(1, got)
+
+	_, err = MustCompile("a,b,0").Int(m, Type)
+	assert.Error(err)
+
+	s, err := MustCompile("a,b,1").Str(m, None)
+	assert.NoError(err)
+	assert.Equal("2", s)
+
+	f, err := MustCompile("a,b,2").Float(m, None)
+	assert.NoError(err)
+	assert.Equal(3.5, f)
+
+	b, err := MustCompile("a,b,last").Bool(m, None)
+	assert.NoError(err)
+	assert.True(b)
+
+	ss, err := PathSlice[any](MustCompile("a,b"), m, None)
+	assert.NoError(err)
+	assert.Equal([]any{"1", 2, 3.5, true}, ss)
+
+	mm, err := PathMap[string, string](MustCompile("m"), m, None)
+	assert.NoError(err)
+	assert.Equal(map[string]string{"k": "v"}, mm)
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		keys   []string
+		expect []segmentKind
+	}{
+		{[]string{}, []segmentKind{}},
+		{[]string{"a", "*", "b=c"}, []segmentKind{keySegment, wildcardSegment, filterSegment}},
+		{[]string{"..", "a"}, []segmentKind{descentSegment, keySegment}},
+		{[]string{"..a"}, []segmentKind{keySegment}},
+		{[]string{"..*"}, []segmentKind{keySegment}},
+		{[]string{`\..`}, []segmentKind{keySegment}},
+		{[]string{`\*`, `\1`}, []segmentKind{keySegment, keySegment}},
+		{[]string{`\len()`, `\1:2`, `\a=b`}, []segmentKind{keySegment, keySegment, keySegment}},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		segs, err := parseKeys(tt.keys)
+		assert.Nilf(err, "keys: %v", tt.keys)
+
+		got := make([]segmentKind, len(segs))
+		for i, seg := range segs {
+			got[i] = seg.kind
+		}
+		assert.Equalf(tt.expect, got, "keys: %v", tt.keys)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		key    string
+		length int
+		expect []int
+		ok     bool
+	}{
+		{"1:3", 5, []int{1, 2}, true},
+		{":", 3, []int{0, 1, 2}, true},
+		{"::2", 5, []int{0, 2, 4}, true},
+		{"1::2", 6, []int{1, 3, 5}, true},
+		{"-2:", 5, []int{3, 4}, true},
+		{":-1", 3, []int{0, 1}, true},
+		{"::-1", 3, []int{2, 1, 0}, true},
+		{"3:0:-2", 5, []int{3, 1}, true},
+		{"-1:-4:-1", 5, []int{4, 3, 2}, true},
+		{"10:20", 5, []int{}, true},
+		{"-10:2", 5, []int{0, 1}, true},
+		{"2:1", 5, []int{}, true},
+		{" 1 : 2 ", 5, []int{1}, true},
+		{"a:b", 0, nil, false},
+		{"1:2:3:4", 0, nil, false},
+		{"1:2:0", 0, nil, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		rng, ok := parseRange(tt.key)
+		assert.Equalf(tt.ok, ok, "key: %s", tt.key)
+		if ok {
+			assert.Equalf(tt.expect, rng.indices(tt.length), "key: %s", tt.key)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	type item struct {
+		Name string
+		tag  string
+	}
+	type box struct {
+		Items []item
+		arr   [4]int
+		any   []any
+		Meta  map[string]int
+	}
+
+	b := box{
+		Items: []item{{"a", "x"}, {"b", "y"}, {"c", "z"}},
+		arr:   [4]int{1, 2, 3, 4},
+		any:   []any{1, nil, "s"},
+		Meta:  map[string]int{"1:2": 12, "10:00:00": 10},
+	}
+
+	assert := assert.New(t)
+
+	assert.Equal([]item{{"b", "y"}, {"c", "z"}}, Any(b, "Items,1:"))
+	assert.Equal([]int{4, 2}, Any(b, "arr,::-2"))
+	assert.Equal([]any{nil, "s"}, Any(b, "any,1:3"))
+	assert.Equal([]int{}, Any(b, "arr,5:"))
+	assert.Equal("b", Any(b, "Items,1:,Name"))
+	assert.Equal([]string{"a", "c"}, All[string](b, "Items,::2,Name"))
+	assert.Equal([]string{"z", "y", "x"}, All[string](b, "Items,::-1,tag"))
+	assert.Equal(12, Any(b, "Meta,1:2"))
+	assert.Equal(10, Any(b, "Meta,10:00:00"))
+
+	matches, err := AllResult[string](b, None, "Items,-2:,Name")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{{[]string{"Items", "1", "Name"}, "b"}, {[]string{"Items", "2", "Name"}, "c"}}, matches)
+
+	_, err = AllResult[string](b, None, "Items,5:,Name")
+	assert.Error(err)
+	_, err = AllResult[string](b, Safe, "Items,:,tag")
+	assert.Error(err)
+	_, err = AnyResult(b, None, "Items,::0")
+	assert.Error(err)
+}
+
+func TestLookupPaths(t *testing.T) {
+	type item struct {
+		Name string
+	}
+	obj := map[string]any{
+		"a":     map[string]any{"b": 1, "nil": nil},
+		"x,y":   2,
+		"items": []item{{"p"}, {"q"}},
+		"named": map[string]int{"Key": 3},
+	}
+
+	tests := []struct {
+		paths []string
+		want  any
+		ok    bool
+	}{
+		{[]string{"a,b"}, 1, true},
+		{[]string{"a", " b "}, 1, true},
+		{[]string{"a,nil"}, nil, true},
+		{[]string{"items,last,name"}, "q", true},
+		{[]string{"named,key"}, 3, true},
+		{[]string{"a,missing"}, nil, false},
+		{[]string{`x\,y`}, nil, false},
+		{[]string{"items,*,name"}, nil, false},
+		{[]string{"items,name=p,name"}, nil, false},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, ok := lookupPaths(reflect.ValueOf(obj), None, tt.paths)
+		if !assert.Equalf(tt.ok, ok, "paths: %v", tt.paths) || !ok {
+			continue
+		}
+		assert.Equalf(tt.want, valueToAny(got), "paths: %v", tt.paths)
+	}
+}
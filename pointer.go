@@ -0,0 +1,119 @@
+package goget
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Pointer like [Any], but queries by a JSON Pointer (RFC 6901), such as "/address/city".
+func Pointer(obj any, pointer string) any {
+	v, _ := PointerResult(obj, None, pointer)
+	return v
+}
+
+// PointerResult like [AnyResult], but queries by a JSON Pointer (RFC 6901), such as "/address/city".
+// Reference tokens are taken literally, "~1" and "~0" escape "/" and "~". The empty pointer is the object itself.
+func PointerResult(obj any, opt Option, pointer string) (_ any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	segs, parseErr := parsePointer(pointer)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return resultToAny[any](querySegments(obj, opt, defaultTagNames, segs), opt&Type == Type)
+}
+
+// CompilePointer parses a JSON Pointer (RFC 6901) into a [Path] and reports syntax errors.
+func CompilePointer(pointer string) (*Path, error) {
+	segs, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Path{segs: segs, tagNames: defaultTagNames}, nil
+}
+
+// MustCompilePointer like [CompilePointer], but panics on error.
+func MustCompilePointer(pointer string) *Path {
+	p, err := CompilePointer(pointer)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// parsePointer parses a JSON Pointer to literal key segments.
+func parsePointer(pointer string) ([]segment, *QueryError) {
+	if pointer == "" {
+		return []segment{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, newQueryError(nil, ErrSyntax, "json pointer must start with /: %s", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	segs := make([]segment, 0, len(tokens))
+	for _, token := range tokens {
+		key, err := unescapePointerToken(token)
+		if err != nil {
+			return nil, newQueryError(err, ErrSyntax, "invalid json pointer: %s", pointer)
+		}
+
+		seg := segment{key: key}
+		if isPointerIndex(key) {
+			if index, err := strconv.Atoi(key); err == nil {
+				seg.index, seg.isIndex = index, true
+			}
+		}
+		segs = append(segs, seg)
+	}
+
+	return segs, nil
+}
+
+// unescapePointerToken decodes "~1" to "/" and "~0" to "~" in a reference token.
+func unescapePointerToken(token string) (string, error) {
+	if !strings.Contains(token, "~") {
+		return token, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(token); i++ {
+		if token[i] != '~' {
+			b.WriteByte(token[i])
+			continue
+		}
+
+		if i+1 == len(token) || (token[i+1] != '0' && token[i+1] != '1') {
+			return "", newQueryError(nil, ErrSyntax, "invalid escape in token %s", token)
+		}
+		if token[i+1] == '0' {
+			b.WriteByte('~')
+		} else {
+			b.WriteByte('/')
+		}
+		i++
+	}
+
+	return b.String(), nil
+}
+
+// isPointerIndex reports whether a reference token is an array index: "0" or digits without leading zeros.
+func isPointerIndex(token string) bool {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return false
+	}
+	for i := 0; i < len(token); i++ {
+		if token[i] < '0' || token[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
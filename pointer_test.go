@@ -0,0 +1,122 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		pointer string
+		keys    []string
+		indexes []bool
+		err     bool
+	}{
+		{"", []string{}, []bool{}, false},
+		{"/", []string{""}, []bool{false}, false},
+		{"/a/b", []string{"a", "b"}, []bool{false, false}, false},
+		{"/a~1b/m~0n", []string{"a/b", "m~n"}, []bool{false, false}, false},
+		{"/~01", []string{"~1"}, []bool{false}, false},
+		{"/tags/0/10", []string{"tags", "0", "10"}, []bool{false, true, true}, false},
+		{"/01/-1/-", []string{"01", "-1", "-"}, []bool{false, false, false}, false},
+		{"/first/last/a,b/x=y/1:2/*", []string{"first", "last", "a,b", "x=y", "1:2", "*"}, []bool{false, false, false, false, false, false}, false},
+		{" /a", nil, nil, true},
+		{"a/b", nil, nil, true},
+		{"/a~", nil, nil, true},
+		{"/a~2", nil, nil, true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		segs, err := parsePointer(tt.pointer)
+		if tt.err {
+			if assert.NotNilf(err, "pointer: %s", tt.pointer) {
+				assert.Equalf(ErrSyntax, err.Code, "pointer: %s", tt.pointer)
+			}
+			continue
+		}
+
+		if !assert.Nilf(err, "pointer: %s", tt.pointer) {
+			continue
+		}
+		indexes := make([]bool, len(segs))
+		for i, seg := range segs {
+			assert.Equalf(keySegment, seg.kind, "pointer: %s", tt.pointer)
+			indexes[i] = seg.isIndex
+		}
+		assert.Equalf(tt.keys, segmentsToKeys(segs), "pointer: %s", tt.pointer)
+		assert.Equalf(tt.indexes, indexes, "pointer: %s", tt.pointer)
+	}
+}
+
+func TestPointer(t *testing.T) {
+	type address struct {
+		City   string
+		street string
+	}
+
+	obj := map[string]any{
+		"address": &address{"Mesa", "123 Main St"},
+		"tags":    []any{"a", "b", "c"},
+		"a/b":     1,
+		"m~n":     2,
+		"":        3,
+		"x,y":     4,
+		"first":   5,
+		" ":       6,
+	}
+
+	tests := []struct {
+		pointer string
+		opt     Option
+		want    any
+		err     bool
+	}{
+		{"", None, obj, false},
+		{"/address/City", None, "Mesa", false},
+		{"/address/city", None, "Mesa", false},
+		{"/address/city", Case, nil, true},
+		{"/address/street", None, "123 Main St", false},
+		{"/address/street", Safe, nil, true},
+		{"/tags/1", None, "b", false},
+		{"/tags/-1", None, nil, true},
+		{"/tags/01", None, nil, true},
+		{"/tags/-", None, nil, true},
+		{"/tags/3", None, nil, true},
+		{"/a~1b", None, 1, false},
+		{"/m~0n", None, 2, false},
+		{"/", None, 3, false},
+		{"/x,y", None, 4, false},
+		{"/first", None, 5, false},
+		{"/ ", None, 6, false},
+		{"/tags/first", None, nil, true},
+		{"/missing", None, nil, true},
+		{"address", None, nil, true},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		got, err := PointerResult(obj, tt.opt, tt.pointer)
+		if tt.err {
+			assert.Errorf(err, "pointer: %s", tt.pointer)
+			continue
+		}
+
+		if !assert.NoErrorf(err, "pointer: %s", tt.pointer) {
+			continue
+		}
+		assert.Equalf(tt.want, got, "pointer: %s", tt.pointer)
+	}
+
+	assert.Equal("c", Pointer(obj, "/tags/2"))
+	assert.Nil(Pointer(obj, "/tags/9"))
+
+	p := MustCompilePointer("/tags/0")
+	s, err := p.Str(obj, None)
+	assert.NoError(err)
+	assert.Equal("a", s)
+
+	_, err = CompilePointer("tags")
+	assert.Error(err)
+	assert.Panics(func() { MustCompilePointer("/~") })
+}
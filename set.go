@@ -0,0 +1,689 @@
+package goget
+
+import (
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// Set assigns a value to the element of an object by path, converting the value to the type of the element unless
+// option Type. The object must be a pointer, map or slice, so the change is visible to the caller.
+//
+// Keys are resolved like queries: fields and map keys are case-insensitive unless option Case, slice indexes may be
+// negative, first or last, a range sets each of its elements, a wildcard sets every child and a filter sets the first
+// matched element, or every one if specified by the filter. Recursive descent and functions are ErrUnsupported errors.
+// A missing map key at the end of the path is added, other missing elements are errors. Children selected by a
+// range, wildcard or filter which miss the remaining path are skipped, any other error fails the update.
+func Set(obj any, value any, opt Option, paths ...string) error {
+	return set(obj, value, opt, false, paths)
+}
+
+// SetCreate like [Set], but creates missing elements on the way down, like mkdir -p: nil pointers are allocated,
+// missing map entries are created, nil maps are initialized and slices are grown. A nil interface becomes []any for an
+// index, otherwise map[string]any. Negative indexes, ranges, wildcards, filters and arrays never create elements.
+func SetCreate(obj any, value any, opt Option, paths ...string) error {
+	return set(obj, value, opt, true, paths)
+}
+
+// set assigns a value to the element of an object by paths, and creates missing elements on the way down if create.
+func set(obj any, value any, opt Option, create bool, paths []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newQueryError(nil, ErrNotFound, "%v", r)
+			return
+		}
+	}()
+
+	segs, parseErr := parsePaths(paths)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	newValue := reflect.ValueOf(value)
+	u := &updater{opt: opt, create: create, update: func(old reflect.Value) (reflect.Value, *QueryError) {
+		return convertValue(newValue, old.Type(), opt&Type == Type)
+	}}
+	if err := u.updateRoot(reflect.ValueOf(obj), segs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// updater search a Value by segments like walker, and replaces every result element by the result of update, or
+// removes it from its parent if remove. Elements which cannot be set in place, such as map values and values in
+// interfaces, are copied, updated and written back to their parents.
+type updater struct {
+	opt    Option
+	root   reflect.Value                                        // the updated object, referenced by filters
+	update func(old reflect.Value) (reflect.Value, *QueryError) // returns the new value of a result element
+	remove bool                                                 // remove result elements instead of updating them
+	create bool                                                 // create missing elements on the way down
+}
+
+// updateRoot updates an object by segments. The object must be a pointer, map or slice, which is updated in place.
+func (u *updater) updateRoot(root reflect.Value, segs []segment) *QueryError {
+	u.root = root
+
+	switch root.Kind() {
+	case reflect.Pointer:
+		if root.IsNil() {
+			return newQueryError(nil, ErrNotFound, "[update] nil pointer")
+		}
+		// The element of a pointer is settable, so the object itself may be replaced
+		elem := root.Elem()
+		newElem, err := u.walk(elem, segs)
+		if err != nil {
+			return err
+		}
+		elem.Set(newElem)
+		return nil
+
+	case reflect.Map, reflect.Slice:
+		if len(segs) == 0 {
+			return newQueryError(nil, ErrTypeMatch, "[update] cannot replace %s object, use a pointer", root.Kind())
+		}
+		if root.IsNil() {
+			return newQueryError(nil, ErrNotFound, "[update] nil %s object, use a pointer", root.Kind())
+		}
+		newRoot, err := u.walk(root, segs)
+		if err != nil {
+			return err
+		}
+		if root.Kind() == reflect.Slice && newRoot.Len() != root.Len() {
+			return newQueryError(nil, ErrTypeMatch, "[update] cannot resize slice object, use a pointer")
+		}
+		return nil
+	}
+
+	return newQueryError(nil, ErrTypeMatch, "[update] object must be a pointer, map or slice, got %s", root.Kind())
+}
+
+// walk search a Value by segments and updates result elements.
+// It returns the updated Value, which the caller must write back.
+func (u *updater) walk(value reflect.Value, segs []segment) (reflect.Value, *QueryError) {
+	if !value.IsValid() {
+		return value, newQueryError(nil, ErrNotFound, "invalid value")
+	}
+
+	if len(segs) == 0 {
+		return u.update(value)
+	}
+	seg := segs[0]
+	currentKey := seg.key
+	remainSegs := segs[1:]
+
+	// A special key of paths yields to an existing map key or struct field of the same key
+	if seg.literal && isLiteralKeyOf(value, currentKey, u.opt, defaultTagNames) {
+		seg.kind = keySegment
+	}
+
+	switch seg.kind {
+	case keySegment, filterSegment, wildcardSegment, rangeSegment:
+	case functionSegment:
+		return value, newQueryError(nil, ErrUnsupported, "[update] function %s is not supported in updates", currentKey)
+	case descentSegment:
+		return value, newQueryError(nil, ErrUnsupported, "[update] recursive descent is not supported in updates")
+	default:
+		return value, newQueryError(nil, ErrUnsupported, "[update] key %s is not supported in updates", currentKey)
+	}
+
+	create := u.create && seg.kind == keySegment
+
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			if !create {
+				return value, newQueryError(nil, ErrNotFound, "[update] nil interface by key %s", currentKey)
+			}
+			return u.walkCreated(value, newContainer(seg), segs)
+		}
+
+		// The element of an interface is a copy, write back the updated element
+		newElem, err := u.walk(writable(value.Elem()), segs)
+		if err != nil {
+			return value, err
+		}
+		newValue := reflect.New(value.Type()).Elem()
+		newValue.Set(newElem)
+		return newValue, nil
+
+	case reflect.Pointer:
+		if value.IsNil() {
+			if !create {
+				return value, newQueryError(nil, ErrNotFound, "[update] nil pointer by key %s", currentKey)
+			}
+			return u.walkCreated(value, reflect.New(value.Type().Elem()), segs)
+		}
+
+		elem := writable(value.Elem())
+		newElem, err := u.walk(elem, segs)
+		if err != nil {
+			return value, err
+		}
+		elem.Set(newElem)
+		return value, nil
+
+	case reflect.Map:
+		return u.walkMap(writable(value), seg, remainSegs)
+
+	case reflect.Struct:
+		// Fields of an unaddressable struct cannot be set, update a copy
+		value = addressable(value)
+		return value, u.walkStruct(value, seg, remainSegs)
+
+	case reflect.Slice, reflect.Array:
+		// Elements of an unaddressable array cannot be set, update a copy
+		if value.Kind() == reflect.Array {
+			value = addressable(value)
+		}
+		return u.walkSlice(value, seg, remainSegs)
+	}
+
+	return value, newQueryError(nil, ErrNotFound, "invalid kind: %s", value.Kind())
+}
+
+// walkCreated updates a created element in place of a nil Value by segments, and returns it as the type of the Value.
+func (u *updater) walkCreated(value reflect.Value, created reflect.Value, segs []segment) (reflect.Value, *QueryError) {
+	newElem, err := u.walk(created, segs)
+	if err != nil {
+		return value, err
+	}
+
+	newValue := reflect.New(value.Type()).Elem()
+	newValue.Set(newElem)
+	return newValue, nil
+}
+
+// newContainer returns a new container for a key in place of a nil interface, []any for an index, otherwise
+// map[string]any.
+func newContainer(seg segment) reflect.Value {
+	if seg.isIndex {
+		return reflect.ValueOf([]any{})
+	}
+	return reflect.ValueOf(map[string]any{})
+}
+
+// walkChild updates a child of a container by the remaining segments.
+// It returns an invalid Value if the child is to be removed by its container.
+func (u *updater) walkChild(child reflect.Value, segs []segment) (reflect.Value, *QueryError) {
+	if u.remove && len(segs) == 0 {
+		return reflect.Value{}, nil
+	}
+
+	return u.walk(child, segs)
+}
+
+// walkMap updates map values by a segment and the remaining segments. Removed values are deleted.
+func (u *updater) walkMap(value reflect.Value, seg segment, segs []segment) (reflect.Value, *QueryError) {
+	// Filter map values
+	if seg.kind == wildcardSegment || seg.kind == filterSegment {
+		if seg.err != nil {
+			return value, seg.err
+		}
+
+		found := false
+		for _, mapKeyValue := range sortedMapKeys(value) {
+			child := value.MapIndex(mapKeyValue)
+			if seg.kind == filterSegment && !seg.filter.match(child, u.walker()) {
+				continue
+			}
+
+			newChild, err := u.walkChild(child, segs)
+			if err != nil {
+				// Only children missing the remaining path are skipped, other errors fail the whole update
+				if err.Code != ErrNotFound {
+					return value, err
+				}
+				continue
+			}
+			value.SetMapIndex(mapKeyValue, newChild)
+			found = true
+
+			if seg.kind == filterSegment && !seg.every {
+				break
+			}
+		}
+
+		if !found {
+			return value, newQueryError(nil, ErrNotFound, "[map filter] no elem by key: %s", seg.key)
+		}
+		return value, nil
+	}
+	keyValue, findErr := findMapKey(value, seg.key, u.opt)
+	if findErr != nil {
+		return value, findErr
+	}
+
+	// A missing key is added at the end of the path, or on the way down if create
+	child := value.MapIndex(keyValue)
+	if !child.IsValid() {
+		if (len(segs) > 0 && !u.create) || u.remove {
+			return value, newQueryError(nil, ErrNotFound, "[map] value not found by key %s", seg.key)
+		}
+		child = reflect.Zero(value.Type().Elem())
+	}
+	if value.IsNil() {
+		if !u.create {
+			return value, newQueryError(nil, ErrNotFound, "[map] nil map by key %s", seg.key)
+		}
+		value = reflect.MakeMap(value.Type())
+	}
+
+	newChild, err := u.walkChild(child, segs)
+	if err != nil {
+		return value, newQueryError(err, err.Code, "[map] update keys: %s", segmentsToKeys(segs))
+	}
+	value.SetMapIndex(keyValue, newChild)
+	return value, nil
+}
+
+// walkStruct updates fields of an addressable struct by a segment and the remaining segments. Removed fields are
+// zeroed.
+func (u *updater) walkStruct(value reflect.Value, seg segment, segs []segment) *QueryError {
+	safe := u.opt&Safe == Safe
+
+	if seg.kind == wildcardSegment || seg.kind == filterSegment {
+		if seg.err != nil {
+			return seg.err
+		}
+
+		found := false
+		for i := 0; i < value.NumField(); i++ {
+			if isFieldHidden(value.Type().Field(i), u.opt, defaultTagNames) {
+				continue
+			}
+
+			field := writable(value.Field(i))
+			if seg.kind == filterSegment && !seg.filter.match(field, u.walker()) {
+				continue
+			}
+
+			newField, err := u.walkChild(field, segs)
+			if err != nil {
+				if err.Code != ErrNotFound {
+					return err
+				}
+				continue
+			}
+			setOrZero(field, newField)
+			found = true
+
+			if seg.kind == filterSegment && !seg.every {
+				break
+			}
+		}
+
+		if !found {
+			return newQueryError(nil, ErrNotFound, "[struct filter] no elem by key: %s", seg.key)
+		}
+		return nil
+	}
+
+	field, ok, findErr := findStructField(value.Type(), seg.key, u.opt, defaultTagNames)
+	if findErr != nil {
+		return findErr
+	}
+	if !ok {
+		return newQueryError(nil, ErrNotFound, "[struct] field %s not exists", seg.key)
+	}
+	if safe && !field.IsExported() {
+		return newQueryError(nil, ErrNotFound, "[struct] field %s not exported", seg.key)
+	}
+
+	fieldValue, err := fieldByIndex(value, field.Index, u.create)
+	if err != nil {
+		return newQueryError(err, ErrNotFound, "[struct] field %s in nil embedded struct", field.Name)
+	}
+
+	newField, err := u.walkChild(fieldValue, segs)
+	if err != nil {
+		return newQueryError(err, err.Code, "[struct] update keys: %s", segmentsToKeys(segs))
+	}
+	setOrZero(fieldValue, newField)
+	return nil
+}
+
+// fieldByIndex returns the writable nested field of an addressable struct by index sequence.
+// Nil embedded pointers on the way are allocated if create, otherwise error.
+func fieldByIndex(value reflect.Value, index []int, create bool) (reflect.Value, *QueryError) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				if !create {
+					return value, newQueryError(nil, ErrNotFound, "nil embedded %s", value.Type())
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = writable(value.Field(x))
+	}
+
+	return value, nil
+}
+
+// walkSlice updates elements of a slice or an addressable array by a segment and the remaining segments.
+// It returns the slice, which is grown if create, or shortened by removed elements. Removed elements of an
+// array are zeroed.
+func (u *updater) walkSlice(value reflect.Value, seg segment, segs []segment) (reflect.Value, *QueryError) {
+	if seg.kind != keySegment {
+		if seg.err != nil {
+			return value, seg.err
+		}
+
+		// A range selects its elements, others select from every element
+		indices := make([]int, value.Len())
+		if seg.kind == rangeSegment {
+			indices = seg.rng.indices(value.Len())
+		} else {
+			for index := range indices {
+				indices[index] = index
+			}
+		}
+
+		found := false
+		removed := make(map[int]bool)
+		for _, index := range indices {
+			elem := writable(value.Index(index))
+			if seg.kind == filterSegment && !seg.filter.match(elem, u.walker()) {
+				continue
+			}
+
+			newElem, err := u.walkChild(elem, segs)
+			if err != nil {
+				if err.Code != ErrNotFound {
+					return value, err
+				}
+				continue
+			}
+			if newElem.IsValid() {
+				elem.Set(newElem)
+			} else {
+				removed[index] = true
+			}
+			found = true
+
+			if seg.kind == filterSegment && !seg.every {
+				break
+			}
+		}
+
+		if !found {
+			return value, newQueryError(nil, ErrNotFound, "[slice filter] no elem by key: %s", seg.key)
+		}
+		return removeElems(value, removed), nil
+	}
+
+	// Grow the slice to the index if create
+	if seg.isIndex && seg.index >= value.Len() && value.Kind() == reflect.Slice && u.create {
+		grown := seg.index + 1 - value.Len()
+		value = reflect.AppendSlice(value, reflect.MakeSlice(value.Type(), grown, grown))
+	}
+	index, ok := sliceIndex(seg, value.Len())
+	if !ok {
+		return value, invalidIndexError(seg)
+	}
+
+	elem := writable(value.Index(index))
+	newElem, err := u.walkChild(elem, segs)
+	if err != nil {
+		return value, newQueryError(err, err.Code, "[slice] update keys: %s", segmentsToKeys(segs))
+	}
+	if !newElem.IsValid() {
+		return removeElems(value, map[int]bool{index: true}), nil
+	}
+	elem.Set(newElem)
+	return value, nil
+}
+
+// removeElems returns a new slice without elements at removed indexes, the original slice is not modified.
+// Arrays cannot be shortened, their removed elements are zeroed.
+func removeElems(value reflect.Value, removed map[int]bool) reflect.Value {
+	if len(removed) == 0 {
+		return value
+	}
+
+	if value.Kind() == reflect.Array {
+		for index := range removed {
+			setOrZero(writable(value.Index(index)), reflect.Value{})
+		}
+		return value
+	}
+
+	shortened := reflect.MakeSlice(value.Type(), 0, value.Len()-len(removed))
+	for index := 0; index < value.Len(); index++ {
+		if !removed[index] {
+			shortened = reflect.Append(shortened, writable(value.Index(index)))
+		}
+	}
+	return shortened
+}
+
+// setOrZero sets a writable Value to a new value, or zero if the new value is invalid.
+func setOrZero(value reflect.Value, newValue reflect.Value) {
+	if !newValue.IsValid() {
+		newValue = reflect.Zero(value.Type())
+	}
+	value.Set(newValue)
+}
+
+// walker returns a walker to match filters.
+func (u *updater) walker() *walker {
+	return &walker{opt: u.opt, tagNames: defaultTagNames, root: u.root}
+}
+
+// addressable returns a Value itself if it is addressable, otherwise an addressable copy.
+func addressable(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return value
+	}
+
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(reflect.ValueOf(valueToAny(value)))
+	return copied
+}
+
+// writable returns a Value which can be modified even if it is obtained from unexported fields.
+// An addressable Value is settable after it is made writable.
+func writable(value reflect.Value) reflect.Value {
+	if value.CanInterface() {
+		return value
+	}
+
+	if value.CanAddr() {
+		return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem()
+	}
+
+	// Copy the value, maps, pointers and slices still refer to the same elements
+	return reflect.ValueOf(valueToAny(value))
+}
+
+// convertValue converts a Value to a type, on a best-effort basis unless typeStrict.
+// Strings are parsed into the type like map keys, and slices and maps are converted element by element.
+func convertValue(value reflect.Value, typ reflect.Type, typeStrict bool) (reflect.Value, *QueryError) {
+	if !value.IsValid() {
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(typ), nil
+		}
+		return value, newQueryError(nil, ErrTypeMatch, "value type not match: need %s got nil", typ)
+	}
+
+	if value.Type().AssignableTo(typ) {
+		return value, nil
+	}
+
+	if typeStrict {
+		return value, newQueryError(nil, ErrTypeMatch, "value type not match: need %s got %s", typ, value.Type())
+	}
+
+	// Convert the concret element of interfaces and pointers
+	if value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return convertValue(reflect.Value{}, typ, typeStrict)
+		}
+		return convertValue(value.Elem(), typ, typeStrict)
+	}
+
+	if value.Kind() == reflect.String && typ.Kind() != reflect.String {
+		converted, err := stringToMapKeyType(value.String(), typ)
+		if err != nil {
+			return value, newQueryError(err, ErrTypeMatch, "cannot convert %q to %s", value.String(), typ)
+		}
+		return converted, nil
+	}
+
+	converted := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		converted.SetString(valueToString(value))
+		return converted, nil
+
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		switch value.Kind() {
+		case reflect.Bool, reflect.Complex64, reflect.Complex128:
+		default:
+			if !isNumberKind(value.Kind()) {
+				return value, newQueryError(nil, ErrTypeMatch, "cannot convert %s to %s", value.Type(), typ)
+			}
+		}
+		if !setNumber(converted, value) {
+			return value, newQueryError(nil, ErrTypeMatch, "cannot convert %s %v to %s", value.Type(), valueToAny(value), typ)
+		}
+		return converted, nil
+
+	case reflect.Pointer:
+		elem, err := convertValue(value, typ.Elem(), typeStrict)
+		if err != nil {
+			return value, err
+		}
+		converted.Set(reflect.New(typ.Elem()))
+		converted.Elem().Set(elem)
+		return converted, nil
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			break
+		}
+		if typ.Kind() == reflect.Slice {
+			converted.Set(reflect.MakeSlice(typ, value.Len(), value.Len()))
+		} else if value.Len() != typ.Len() {
+			return value, newQueryError(nil, ErrTypeMatch, "cannot convert %s of length %d to %s", value.Type(), value.Len(), typ)
+		}
+		for i := 0; i < value.Len(); i++ {
+			elem, err := convertValue(value.Index(i), typ.Elem(), typeStrict)
+			if err != nil {
+				return value, err
+			}
+			converted.Index(i).Set(elem)
+		}
+		return converted, nil
+
+	case reflect.Map:
+		if value.Kind() != reflect.Map {
+			break
+		}
+		converted.Set(reflect.MakeMapWithSize(typ, value.Len()))
+		for _, mapKeyValue := range value.MapKeys() {
+			key, err := convertValue(mapKeyValue, typ.Key(), typeStrict)
+			if err != nil {
+				return value, err
+			}
+			elem, err := convertValue(value.MapIndex(mapKeyValue), typ.Elem(), typeStrict)
+			if err != nil {
+				return value, err
+			}
+			converted.SetMapIndex(key, elem)
+		}
+		return converted, nil
+	}
+
+	if value.Type().ConvertibleTo(typ) {
+		return value.Convert(typ), nil
+	}
+
+	return value, newQueryError(nil, ErrTypeMatch, "cannot convert %s to %s", value.Type(), typ)
+}
+
+// setNumber sets a bool, number or complex Value to a bool, number or complex Value of another type.
+// It returns false if the value is out of the range of the type, negative for an unsigned type, not integral for an
+// integer type, or has an imaginary part for a type other than complex.
+func setNumber(converted reflect.Value, value reflect.Value) bool {
+	switch converted.Kind() {
+	case reflect.Bool:
+		converted.SetBool(valueToBool(value))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i = value.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if value.Uint() > math.MaxInt64 {
+				return false
+			}
+			i = int64(value.Uint())
+		default:
+			f, ok := realValue(value)
+			if !ok || f != math.Trunc(f) || f < -(1<<63) || f >= 1<<63 {
+				return false
+			}
+			i = int64(f)
+		}
+		if converted.OverflowInt(i) {
+			return false
+		}
+		converted.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if value.Int() < 0 {
+				return false
+			}
+			u = uint64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u = value.Uint()
+		default:
+			f, ok := realValue(value)
+			if !ok || f != math.Trunc(f) || f < 0 || f >= 1<<64 {
+				return false
+			}
+			u = uint64(f)
+		}
+		if converted.OverflowUint(u) {
+			return false
+		}
+		converted.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, ok := realValue(value)
+		if !ok || converted.OverflowFloat(f) {
+			return false
+		}
+		converted.SetFloat(f)
+
+	default:
+		c := valueToComplex(value)
+		if converted.OverflowComplex(c) {
+			return false
+		}
+		converted.SetComplex(c)
+	}
+
+	return true
+}
+
+// realValue returns a bool, number or complex Value as float64, and false ok if it has an imaginary part.
+func realValue(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Complex64, reflect.Complex128:
+		c := value.Complex()
+		return real(c), imag(c) == 0
+	}
+	return valueToFloat(value), true
+}
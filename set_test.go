@@ -0,0 +1,293 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// testAddress and testPerson are the fixture of the update tests, see [newTestPerson].
+type testAddress struct {
+	City   string
+	Zip    int
+	Street string
+	Tags   []string
+	note   string
+}
+
+type testPerson struct {
+	Name     string
+	Age      uint8
+	Address  testAddress
+	Home     *testAddress
+	Tags     []string
+	Scores   [3]int
+	Meta     map[string]any
+	Born     time.Time
+	Friends  []testAddress
+	Contacts map[string]testAddress
+	Lists    map[string][]int
+	Ptr      *[]int
+	private  map[string]int
+	secret   []int
+}
+
+// newTestPerson returns a new fixture for each case of the update tests, which may modify it.
+func newTestPerson() *testPerson {
+	ptr := []int{1}
+	return &testPerson{
+		Name:    "Ann",
+		Age:     30,
+		Address: testAddress{City: "Mesa", Zip: 85201, note: "Main St"},
+		Home:    &testAddress{City: "Dover", Zip: 19901, Street: "Elm St"},
+		Tags:    []string{"a", "b", "c"},
+		Scores:  [3]int{1, 2, 3},
+		Meta: map[string]any{
+			"Level": 1,
+			"Token": "t",
+			"inner": map[string]any{"x": 1, "y": 2},
+			"addr":  testAddress{City: "Lima", Tags: []string{"x"}},
+			"list":  []any{1, 2, 3},
+			"count": 1,
+		},
+		Friends:  []testAddress{{City: "Rome", Street: "Main St"}, {City: "Oslo", Street: "Oak St"}, {City: "Rome", Street: "Pine St"}},
+		Contacts: map[string]testAddress{"work": {City: "Kyiv", Street: "Main St"}},
+		Lists:    map[string][]int{"a": {1}},
+		Ptr:      &ptr,
+		private:  map[string]int{"a": 1},
+		secret:   []int{1, 2},
+	}
+}
+
+// updateTest is a case of the update tests: paths and value to update with option, the paths to get the updated
+// element if not the paths themselves, and the wanted element or error code.
+type updateTest struct {
+	paths []string
+	value any
+	opt   Option
+	get   []string
+	want  any
+	code  ErrCode
+}
+
+// testUpdates runs update on a new fixture for each case, and checks the error code or the updated element.
+func testUpdates(t *testing.T, tests []updateTest, update func(p *testPerson, tt updateTest) error) {
+	assert := assert.New(t)
+	for _, tt := range tests {
+		p := newTestPerson()
+		err := update(p, tt)
+		if tt.code != 0 {
+			var queryErr *QueryError
+			if assert.ErrorAsf(err, &queryErr, "paths: %v", tt.paths) {
+				assert.Equalf(tt.code, queryErr.Code, "paths: %v", tt.paths)
+			}
+			continue
+		}
+
+		if !assert.NoErrorf(err, "paths: %v", tt.paths) {
+			continue
+		}
+		get := tt.get
+		if get == nil {
+			get = tt.paths
+		}
+		assert.Equalf(tt.want, Any(p, get...), "paths: %v", tt.paths)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []updateTest{
+		{[]string{"name"}, "Bob", None, nil, "Bob", 0},
+		{[]string{"name"}, "Bob", Case, nil, nil, ErrNotFound},
+		{[]string{"age"}, "42", None, nil, uint8(42), 0},
+		{[]string{"age"}, 42.0, None, nil, uint8(42), 0},
+		{[]string{"age"}, "old", None, nil, nil, ErrTypeMatch},
+		{[]string{"age"}, 42, Type, nil, nil, ErrTypeMatch},
+		{[]string{"age"}, uint8(42), Type, nil, uint8(42), 0},
+		{[]string{"age"}, uint64(255), None, nil, uint8(255), 0},
+		{[]string{"age"}, 300, None, nil, nil, ErrTypeMatch},
+		{[]string{"age"}, -1, None, nil, nil, ErrTypeMatch},
+		{[]string{"age"}, 3.9, None, nil, nil, ErrTypeMatch},
+		{[]string{"age"}, -0.5, None, nil, nil, ErrTypeMatch},
+		{[]string{"age"}, complex(1, 1), None, nil, nil, ErrTypeMatch},
+		{[]string{"scores", "1"}, -5.0, None, nil, -5, 0},
+		{[]string{"scores", "1"}, uint64(1 << 63), None, nil, nil, ErrTypeMatch},
+		{[]string{"scores", "1"}, 1e19, None, nil, nil, ErrTypeMatch},
+		{[]string{"scores"}, []float64{1, 2.5, 3}, None, nil, nil, ErrTypeMatch},
+		{[]string{"address", "city"}, "Tempe", None, nil, "Tempe", 0},
+		{[]string{"address,zip"}, "85281", None, nil, 85281, 0},
+		{[]string{"address", "note"}, "Oak St", None, nil, "Oak St", 0},
+		{[]string{"address", "note"}, "Oak St", Safe, nil, nil, ErrNotFound},
+		{[]string{"home", "city"}, "Camden", None, nil, "Camden", 0},
+		{[]string{"tags", "0"}, "x", None, nil, "x", 0},
+		{[]string{"tags", "-1"}, "z", None, nil, "z", 0},
+		{[]string{"tags", "last"}, "z", None, []string{"tags", "2"}, "z", 0},
+		{[]string{"tags", "3"}, "z", None, nil, nil, ErrNotFound},
+		{[]string{"tags", "=b"}, "y", None, []string{"tags", "1"}, "y", 0},
+		{[]string{"tags"}, []any{"p", "q"}, None, nil, []string{"p", "q"}, 0},
+		{[]string{"scores", "1"}, 5, None, nil, 5, 0},
+		{[]string{"scores"}, []int{7, 8, 9}, None, nil, [3]int{7, 8, 9}, 0},
+		{[]string{"scores"}, []int{7}, None, nil, nil, ErrTypeMatch},
+		{[]string{"meta", "level"}, 2, None, []string{"meta", "Level"}, 2, 0},
+		{[]string{"Meta", "level"}, 2, Case, []string{"Meta", "level"}, 2, 0},
+		{[]string{"meta", "new"}, "v", None, nil, "v", 0},
+		{[]string{"meta", "inner", "x"}, 2, None, nil, 2, 0},
+		{[]string{"meta", "addr", "city"}, "Cusco", None, nil, "Cusco", 0},
+		{[]string{"meta", "missing", "x"}, 2, None, nil, nil, ErrNotFound},
+		{[]string{"born"}, "2006-01-02T15:04:05Z", None, nil, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), 0},
+		{[]string{"friends", "city=Oslo", "city"}, "Bergen", None, []string{"friends", "1", "city"}, "Bergen", 0},
+		{[]string{"friends", "city=Paris", "city"}, "Lyon", None, nil, nil, ErrNotFound},
+		{[]string{"friends", "city=~*", "city"}, "Lyon", None, nil, nil, ErrSyntax},
+		{[]string{"meta", "(x=1"}, "v", None, nil, nil, ErrSyntax},
+		{[]string{"friends", "*", "zip"}, 1, None, []string{"friends", "0", "zip"}, 1, 0},
+		{[]string{"contacts", "work", "city"}, "Lviv", None, nil, "Lviv", 0},
+		{[]string{"private", "a"}, 2, None, nil, 2, 0},
+		{[]string{"private", "a"}, 2, Safe, nil, nil, ErrNotFound},
+		{[]string{"home"}, nil, None, nil, (*testAddress)(nil), 0},
+		{[]string{"name"}, nil, None, nil, nil, ErrTypeMatch},
+		{[]string{"tags", "1:3"}, "x", None, []string{"tags"}, []string{"a", "x", "x"}, 0},
+		{[]string{"tags", "::-2"}, "x", None, []string{"tags"}, []string{"x", "b", "x"}, 0},
+		{[]string{"friends", "0:1", "city"}, "Bari", None, []string{"friends", "*", "city"}, "Bari", 0},
+		{[]string{"tags", "5:9"}, "x", None, nil, nil, ErrNotFound},
+		{[]string{"..", "city"}, "x", None, nil, nil, ErrUnsupported},
+		{[]string{"tags", "len()"}, 1, None, nil, nil, ErrUnsupported},
+	}
+
+	testUpdates(t, tests, func(p *testPerson, tt updateTest) error {
+		return Set(p, tt.value, tt.opt, tt.paths...)
+	})
+
+	assert := assert.New(t)
+
+	// Filters with every match set every element
+	p := newTestPerson()
+	assert.NoError(Set(p, "x", None, "friends,?city!=Paris", "city"))
+	assert.Equal([]string{"x", "x", "x"}, All[string](p, "friends,*,city"))
+
+	// Maps and slices are updated in place, other objects must be pointers
+	m := map[string]any{"a": []int{1, 2}}
+	assert.NoError(Set(m, 3, None, "a,1"))
+	assert.Equal([]int{1, 3}, m["a"])
+	assert.NoError(Set(m, 1, None, "b"))
+	assert.Equal(1, m["b"])
+	s := []testAddress{{City: "Rome"}}
+	assert.NoError(Set(s, "Milan", None, "0,city"))
+	assert.Equal("Milan", s[0].City)
+	assert.Error(Set(testAddress{}, "Milan", None, "city"))
+	assert.Error(Set(m, map[string]any{}, None))
+
+	// The object itself is replaced through a pointer
+	n := 1
+	assert.NoError(Set(&n, "2", None))
+	assert.Equal(2, n)
+
+	// Children missing the remaining path are skipped, other errors fail the update
+	a := &testAddress{City: "Mesa", Zip: 85201}
+	err := Set(a, "x", None, "*")
+	var queryErr *QueryError
+	if assert.ErrorAs(err, &queryErr) {
+		assert.Equal(ErrTypeMatch, queryErr.Code)
+	}
+	friends := []testAddress{{City: "Rome"}, {City: "Oslo"}}
+	assert.Error(Set(&friends, "x", None, "city=~^[RO]", "zip"))
+	assert.Equal(0, friends[1].Zip)
+	assert.NoError(Set(map[string]any{"a": map[string]any{"x": 1}, "b": 2}, 3, None, "*,x"))
+
+	// Existing special keys are set as is
+	stars := map[string]any{"*": 1, "a": 1}
+	assert.NoError(Set(stars, 2, None, "*"))
+	assert.Equal(map[string]any{"*": 2, "a": 1}, stars)
+
+	// Existing keys which are not valid filters are set as is
+	m = map[string]any{"(x=1": 1}
+	assert.NoError(Set(m, 2, None, "(x=1"))
+	assert.Equal(2, m["(x=1"])
+
+	var nilMap map[string]int
+	assert.Error(Set(nilMap, 1, None, "a"))
+	assert.Error(Set(p, "x", None, "tags,["))
+}
+
+func TestSetCreate(t *testing.T) {
+	type address struct {
+		City string
+		Tags []string
+	}
+	type Base struct {
+		ID int
+	}
+	type person struct {
+		*Base
+		Name      string
+		Address   *address
+		Meta      map[string]any
+		Contacts  map[string]*address
+		Friends   []address
+		Addresses []*address
+		Any       any
+	}
+
+	tests := []struct {
+		paths []string
+		value any
+		get   []string
+		want  any
+	}{
+		{[]string{"address", "city"}, "Mesa", nil, "Mesa"},
+		{[]string{"address", "tags", "2"}, "c", nil, "c"},
+		{[]string{"meta", "a", "b"}, 1, nil, 1},
+		{[]string{"meta", "list", "1"}, "x", nil, "x"},
+		{[]string{"contacts", "work", "city"}, "Kyiv", nil, "Kyiv"},
+		{[]string{"friends", "1", "city"}, "Oslo", nil, "Oslo"},
+		{[]string{"friends", "first", "city"}, "Rome", []string{"friends", "0", "city"}, "Rome"},
+		{[]string{"addresses", "0", "city"}, "Lima", nil, "Lima"},
+		{[]string{"any", "a", "0", "b"}, true, nil, true},
+		{[]string{"id"}, 7, nil, 7},
+		{[]string{"base", "id"}, 7, nil, 7},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		p := &person{}
+		if !assert.NoErrorf(SetCreate(p, tt.value, None, tt.paths...), "paths: %v", tt.paths) {
+			continue
+		}
+		get := tt.get
+		if get == nil {
+			get = tt.paths
+		}
+		assert.Equalf(tt.want, Any(p, get...), "paths: %v", tt.paths)
+
+		// Without creating, missing elements are errors
+		assert.Errorf(Set(&person{}, tt.value, None, tt.paths...), "paths: %v", tt.paths)
+	}
+
+	p := &person{}
+	assert.NoError(SetCreate(p, "x", None, "meta,list,2"))
+	assert.Equal([]any{nil, nil, "x"}, p.Meta["list"])
+	assert.NoError(SetCreate(p, "y", None, "friends,2,tags,1"))
+	assert.Equal(3, len(p.Friends))
+	assert.Equal([]string{"", "y"}, p.Friends[2].Tags)
+
+	// Negative indexes, filters and arrays never create
+	assert.Error(SetCreate(p, "x", None, "friends,-9,city"))
+	assert.Error(SetCreate(p, "x", None, "friends,city=Paris,city"))
+	assert.Error(SetCreate(p, "x", None, "friends,5:7,city"))
+	assert.Error(SetCreate(&[1]int{}, 1, None, "3"))
+
+	// Slice objects cannot grow, and nil objects cannot be created
+	s := []int{1}
+	assert.Error(SetCreate(s, 2, None, "3"))
+	assert.Equal([]int{1}, s)
+	assert.NoError(SetCreate(&s, 2, None, "3"))
+	assert.Equal([]int{1, 0, 0, 2}, s)
+	var m map[string]any
+	assert.Error(SetCreate(m, 1, None, "a"))
+	assert.NoError(SetCreate(&m, 1, None, "a,b"))
+	assert.Equal(map[string]any{"a": map[string]any{"b": 1}}, m)
+
+	// Options apply as usual
+	assert.Error(SetCreate(&person{}, "x", Case, "Address,city"))
+	assert.NoError(SetCreate(p, "x", Case, "Address,City"))
+	assert.Equal("x", p.Address.City)
+}
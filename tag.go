@@ -0,0 +1,46 @@
+package goget
+
+import (
+	"reflect"
+	"strings"
+)
+
+// defaultTagNames are the struct tags to match keys with option Tag, unless a compiled [Path] sets others by
+// [Path.WithTagNames].
+var defaultTagNames = []string{"json"}
+
+// tagNamesOnly is an unexported option in the bit not used by exported options. With option Tag, a field named in a tag
+// matches only by that name, like encoding/json, and a field without one by its field name.
+const tagNamesOnly Option = 1
+
+// tagFieldName returns the name in a tag of a field, such as "name" in `json:"name,omitempty"`.
+// The name is empty if the tag has only options, and false ok if the field has no such tag or is ignored by "-".
+func tagFieldName(field reflect.StructField, tagName string) (string, bool) {
+	tag, ok := field.Tag.Lookup(tagName)
+	if !ok || tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	return name, true
+}
+
+// isTagIgnored reports whether a field is ignored by "-" in any of the tags.
+func isTagIgnored(field reflect.StructField, tagNames []string) bool {
+	for _, tagName := range tagNames {
+		if field.Tag.Get(tagName) == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTagName reports whether a field is named in any of the tags.
+func hasTagName(field reflect.StructField, tagNames []string) bool {
+	for _, tagName := range tagNames {
+		if name, ok := tagFieldName(field, tagName); ok && name != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package goget
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFindTaggedField(t *testing.T) {
+	type account struct {
+		UserID   int    `json:"user_id" yaml:"uid"`
+		Name     string `json:",omitempty"`
+		Password string `json:"-"`
+		Dash     string `json:"-,"`
+		Email    string `yaml:"mail"`
+		Label    string `json:"name"`
+		Plain    string
+		lower    string `db:"low"`
+	}
+
+	tests := []struct {
+		tagNames      []string
+		key           string
+		caseSensitive bool
+		want          string
+	}{
+		{[]string{"json"}, "user_id", false, "UserID"},
+		{[]string{"json"}, "USER_ID", false, "UserID"},
+		{[]string{"json"}, "USER_ID", true, ""},
+		{[]string{"json"}, "UserID", true, "UserID"},
+		{[]string{"json"}, "userid", false, "UserID"},
+		{[]string{"json"}, "Name", false, "Name"},
+		{[]string{"json"}, "name", false, "Label"},
+		{[]string{"json"}, "name", true, "Label"},
+		{[]string{"json"}, "password", false, ""},
+		{[]string{"json"}, "Password", true, ""},
+		{[]string{"json"}, "-", false, "Dash"},
+		{[]string{"json"}, "mail", false, ""},
+		{[]string{"json", "yaml"}, "mail", false, "Email"},
+		{[]string{"yaml", "json"}, "uid", false, "UserID"},
+		{[]string{"json"}, "plain", false, "Plain"},
+		{[]string{"db"}, "low", false, "lower"},
+		{[]string{"json"}, "missing", false, ""},
+		{[]string{}, "password", false, "Password"},
+	}
+
+	assert := assert.New(t)
+	for _, tt := range tests {
+		opt := Tag
+		if tt.caseSensitive {
+			opt |= Case
+		}
+
+		got := ""
+		if field, ok, err := findStructField(reflect.TypeOf(account{}), tt.key, opt, tt.tagNames); ok && err == nil {
+			got = field.Name
+		}
+		assert.Equalf(tt.want, got, "tags: %v, key: %s", tt.tagNames, tt.key)
+	}
+}
+
+func TestStructFieldIndexBounded(t *testing.T) {
+	type bounded struct {
+		Name string `json:"name"`
+	}
+
+	countIndexes := func() int {
+		count := 0
+		structFieldIndexes.Range(func(key, _ any) bool {
+			if key.(structFieldIndexKey).typ == reflect.TypeOf(bounded{}) {
+				count++
+			}
+			return true
+		})
+		return count
+	}
+
+	assert := assert.New(t)
+	for i := 0; i < 100; i++ {
+		_, err := AnyResult(bounded{}, Tag, "missing"+strconv.Itoa(i))
+		assert.Error(err)
+		_, err = AnyResult(bounded{}, None, "missing"+strconv.Itoa(i))
+		assert.Error(err)
+	}
+	assert.Equal(2, countIndexes())
+}
+
+func TestTag(t *testing.T) {
+	type profile struct {
+		ZipCode string `json:"zip_code"`
+	}
+	type user struct {
+		UserID   int               `json:"user_id"`
+		Secret   string            `json:"-"`
+		Profile  *profile          `json:"profile,omitempty"`
+		Contacts []profile         `json:"contacts"`
+		Extra    map[string]string `json:"extra"`
+	}
+
+	u := user{
+		UserID:   7,
+		Secret:   "s",
+		Profile:  &profile{"12345"},
+		Contacts: []profile{{"1"}, {"2"}},
+		Extra:    map[string]string{"user_id": "extra"},
+	}
+
+	assert := assert.New(t)
+
+	assert.Equal(7, MayInt(u, Tag, "user_id"))
+	assert.Equal(7, MayInt(u, Tag, "UserID"))
+	assert.Equal("12345", MayString(u, Tag, "profile", "zip_code"))
+	assert.Equal("extra", MayString(u, Tag, "extra", "user_id"))
+	assert.Equal([]string{"1", "2"}, All[string](u, "contacts,*,zipcode"))
+
+	// Without option Tag, only field names match
+	_, err := IntResult(u, None, "user_id")
+	assert.Error(err)
+
+	// Ignored fields
+	assert.Equal("s", MayString(u, None, "secret"))
+	_, err = StringResult(u, Tag, "secret")
+	assert.ErrorContains(err, "secret")
+
+	// Filters and other notations
+	matches, err := AllResult[string](u, Tag, "contacts,?zip_code=2,zip_code")
+	assert.NoError(err)
+	assert.Equal([]Match[string]{{[]string{"Contacts", "1", "ZipCode"}, "2"}}, matches)
+	zip, err := MustCompilePointer("/profile/zip_code").Str(u, Tag)
+	assert.NoError(err)
+	assert.Equal("12345", zip)
+
+	// Wildcards, filters and functions skip ignored fields
+	assert.Equal([]string{"UserID", "Profile", "Contacts", "Extra"}, MayAny(u, Tag, "keys()"))
+	assert.Equal(5, MayInt(u, None, "values(),len()"))
+	assert.Equal(4, MayInt(u, Tag, "values(),len()"))
+	assert.Equal([]any{"s"}, All[any](u, "?=s"))
+	matches, err = AllResult[string](u, Tag, "?=s")
+	assert.Error(err)
+	assert.Nil(matches)
+	assert.Error(Set(&u, "x", Tag, "*=s"))
+	assert.NoError(Set(&u, "x", None, "*=s"))
+	assert.Equal("x", u.Secret)
+
+	// Tags of a compiled path
+	type record struct {
+		ID   int    `json:"id" db:"record_id"`
+		Note string `json:"note" db:"-"`
+	}
+	r := record{1, "n"}
+	p := MustCompile("record_id").WithTagNames("db")
+	id, err := p.Int(r, Tag)
+	assert.NoError(err)
+	assert.Equal(1, id)
+	_, err = MustCompile("record_id").Int(r, Tag)
+	assert.Error(err)
+	_, err = MustCompile("id").WithTagNames().Int(r, Tag|Case)
+	assert.Error(err)
+	names, err := PathSlice[string](MustCompile("keys()").WithTagNames("db"), r, Tag)
+	assert.NoError(err)
+	assert.Equal([]string{"ID"}, names)
+	_, err = MustCompile("note").WithTagNames("db").Str(r, Tag)
+	assert.Error(err)
+	note, err := MustCompile("note").Str(r, Tag)
+	assert.NoError(err)
+	assert.Equal("n", note)
+}